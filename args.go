@@ -1,19 +1,18 @@
 package errors
 
-func makeArgs(funcName string, args ...any) []attr {
-	attrs := make([]attr, 0, len(args)/2)
-	for i := 0; i < len(args); i += 2 {
-		k, ok := args[i].(string)
-		if !ok {
-			break
-		}
+// groupAttrs groups attributes by the name of the function that attached them,
+// keeping the order in which the functions first appear.
+func groupAttrs(attrs []attr, full bool) (attrFunctions []string, attrMap map[string][]attr) {
+	attrMap = make(map[string][]attr, 32)
+	attrFunctions = make([]string, 0, 32)
 
-		attrs = append(attrs, attr{
-			Function: funcName,
-			Key:      k,
-			Value:    args[i+1],
-		})
+	for _, a := range attrs {
+		name := a.name(full)
+		if _, ok := attrMap[name]; !ok {
+			attrFunctions = append(attrFunctions, name)
+		}
+		attrMap[name] = append(attrMap[name], a)
 	}
 
-	return attrs
+	return attrFunctions, attrMap
 }
@@ -0,0 +1,149 @@
+package errors
+
+import (
+	"log/slog"
+	"time"
+)
+
+// badKey is the key of the malformed arguments of With
+const badKey = "!BADKEY"
+
+// Attr is a typed key-value field of an Error
+//
+// An Attr can be passed to With and Template.With alongside the alternating
+// key-value arguments.
+//
+//	err := errors.New("query failed").With(
+//		errors.String("table", "users"),
+//		errors.Duration("elapsed", elapsed),
+//		"retry", 3,
+//	)
+type Attr struct {
+	Key   string
+	Value any
+}
+
+// String returns an Attr for a string value
+func String(key, value string) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+// Int returns an Attr for an int value
+func Int(key string, value int) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+// Int64 returns an Attr for an int64 value
+func Int64(key string, value int64) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+// Float64 returns an Attr for a float64 value
+func Float64(key string, value float64) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+// Bool returns an Attr for a bool value
+func Bool(key string, value bool) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+// Duration returns an Attr for a time.Duration value
+func Duration(key string, value time.Duration) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+// Time returns an Attr for a time.Time value
+func Time(key string, value time.Time) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+// Any returns an Attr for any value
+func Any(key string, value any) Attr {
+	return Attr{Key: key, Value: value}
+}
+
+// Group returns an Attr for a group of Attrs
+//
+// The Attrs of the group are attached with the key of the group as prefix,
+// e.g. Group("db", String("host", "localhost")) is attached as "db.host".
+// The Attrs are attached without prefix if the key is empty, and an empty group
+// is not attached.
+func Group(key string, attrs ...Attr) Attr {
+	return Attr{Key: key, Value: attrs}
+}
+
+// newAttr returns the attribute attached by the function of the caller frame
+func newAttr(caller frame, key string, value any) attr {
+	return attr{
+		Function:     caller.Function,
+		Key:          key,
+		Value:        value,
+		fullFunction: caller.FullFunction,
+	}
+}
+
+// appendAttr appends the Attr, flattening groups with dotted keys
+func appendAttr(attrs []attr, caller frame, prefix string, a Attr) []attr {
+	key := a.Key
+	if prefix != "" && key != "" {
+		key = prefix + "." + key
+	} else if prefix != "" {
+		key = prefix
+	}
+
+	if group, ok := a.Value.([]Attr); ok {
+		for _, a := range group {
+			attrs = appendAttr(attrs, caller, key, a)
+		}
+
+		return attrs
+	}
+
+	return append(attrs, newAttr(caller, key, a.Value))
+}
+
+// makeArgs converts the arguments of With into attributes attached by the caller
+//
+// The arguments are Attrs, slog.Attrs or alternating key-value pairs. A key
+// without a value and an argument which is neither a key nor an Attr are kept
+// with the "!BADKEY" key, like log/slog does.
+func makeArgs(caller frame, args ...any) []attr {
+	attrs := make([]attr, 0, len(args)/2)
+	for i := 0; i < len(args); i++ {
+		switch a := args[i].(type) {
+		case Attr:
+			attrs = appendAttr(attrs, caller, "", a)
+		case slog.Attr:
+			attrs = appendAttr(attrs, caller, "", fromSlogAttr(a))
+		case string:
+			if i+1 >= len(args) {
+				attrs = append(attrs, newAttr(caller, badKey, a))
+				continue
+			}
+
+			attrs = append(attrs, newAttr(caller, a, args[i+1]))
+			i++
+		default:
+			attrs = append(attrs, newAttr(caller, badKey, a))
+		}
+	}
+
+	return attrs
+}
+
+// fromSlogAttr converts the slog.Attr into an Attr, keeping its groups
+func fromSlogAttr(a slog.Attr) Attr {
+	v := a.Value.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return Attr{Key: a.Key, Value: v.Any()}
+	}
+
+	group := v.Group()
+	attrs := make([]Attr, 0, len(group))
+	for _, a := range group {
+		attrs = append(attrs, fromSlogAttr(a))
+	}
+
+	return Group(a.Key, attrs...)
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestMakeArgs(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		args     []any
+		expected []Field
+	}{
+		{
+			"key value",
+			[]any{"k1", "v1", "k2", 2},
+			[]Field{{"fn", "k1", "v1"}, {"fn", "k2", 2}},
+		},
+		{
+			"typed",
+			[]any{String("k1", "v1"), Int("k2", 2), Duration("k3", time.Second), Any("k4", nil)},
+			[]Field{{"fn", "k1", "v1"}, {"fn", "k2", 2}, {"fn", "k3", time.Second}, {"fn", "k4", nil}},
+		},
+		{
+			"mixed",
+			[]any{"k1", "v1", Bool("k2", true), "k3", 3.5},
+			[]Field{{"fn", "k1", "v1"}, {"fn", "k2", true}, {"fn", "k3", 3.5}},
+		},
+		{
+			"group",
+			[]any{Group("db", String("host", "localhost"), Group("pool", Int("size", 10))), Group("", Int("k1", 1)), Group("empty")},
+			[]Field{{"fn", "db.host", "localhost"}, {"fn", "db.pool.size", 10}, {"fn", "k1", 1}},
+		},
+		{
+			"slog",
+			[]any{slog.String("k1", "v1"), slog.Group("g", slog.Int("k2", 2))},
+			[]Field{{"fn", "k1", "v1"}, {"fn", "g.k2", int64(2)}},
+		},
+		{
+			"odd",
+			[]any{"k1", "v1", "k2"},
+			[]Field{{"fn", "k1", "v1"}, {"fn", "!BADKEY", "k2"}},
+		},
+		{
+			"non-string key",
+			[]any{1, "v1", "k2", "v2"},
+			[]Field{{"fn", "!BADKEY", 1}, {"fn", "v1", "k2"}, {"fn", "!BADKEY", "v2"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			attrs := makeArgs(frame{Function: "fn"}, tc.args...)
+			if len(attrs) != len(tc.expected) {
+				t.Fatalf("Expected %v, got %v", tc.expected, attrs)
+			}
+
+			for i := range attrs {
+				if attrs[i].field() != tc.expected[i] {
+					t.Errorf("Expected %v, got %v", tc.expected[i], attrs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithTypedAttrs(t *testing.T) {
+	err := NewTemplate(String("service", "user")).With(Int("version", 2)).New("failed").With(Duration("elapsed", time.Second), "odd")
+
+	attrs := err.(*errorStack).attr
+	expected := []Field{
+		{"TestWithTypedAttrs", "service", "user"},
+		{"TestWithTypedAttrs", "version", 2},
+		{"TestWithTypedAttrs", "elapsed", time.Second},
+		{"TestWithTypedAttrs", "!BADKEY", "odd"},
+	}
+
+	if len(attrs) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, attrs)
+	}
+
+	for i := range attrs {
+		if attrs[i].field() != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], attrs[i])
+		}
+	}
+}
@@ -1,33 +1,92 @@
 package errors
 
-import "testing"
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"testing"
+)
 
 func BenchmarkNew(b *testing.B) {
-	for b.Loop() {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
 		New("test error")
 	}
 }
 
+// BenchmarkNewResolved measures New with the stack symbolized,
+// the difference from BenchmarkNew is the cost saved by lazy stack capture
+func BenchmarkNewResolved(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		New("test error").(*errorStack).stack.frames()
+	}
+}
+
+// BenchmarkNewWith measures New with fields, which resolves only the caller of the stack
+func BenchmarkNewWith(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		New("test error").With("k1", "v1", "k2", 2)
+	}
+}
+
+// BenchmarkNewWithChain measures chained With calls, which share the caller frame
+func BenchmarkNewWithChain(b *testing.B) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		New("test error").With("k1", "v1").With("k2", 2).With("k3", 3)
+	}
+}
+
 func BenchmarkErrorf(b *testing.B) {
+	b.ReportAllocs()
 	err := New("test error")
 
-	for b.Loop() {
+	for i := 0; i < b.N; i++ {
 		Errorf("test error: %w", err)
 	}
 }
 
+// BenchmarkErrorfResolved measures Errorf with the stack symbolized,
+// the difference from BenchmarkErrorf is the cost saved by lazy stack capture
+func BenchmarkErrorfResolved(b *testing.B) {
+	b.ReportAllocs()
+	err := New("test error")
+
+	for i := 0; i < b.N; i++ {
+		Errorf("test error: %w", err).(*errorStack).stack.frames()
+	}
+}
+
 func BenchmarkWrap(b *testing.B) {
+	b.ReportAllocs()
 	err := New("test error")
 
-	for b.Loop() {
+	for i := 0; i < b.N; i++ {
 		Wrapf(err, "test error")
 	}
 }
 
+// BenchmarkWrapResolved measures Wrap with the stack symbolized,
+// the difference from BenchmarkWrap is the cost saved by lazy stack capture
+func BenchmarkWrapResolved(b *testing.B) {
+	b.ReportAllocs()
+	err := New("test error")
+
+	for i := 0; i < b.N; i++ {
+		Wrapf(err, "test error").(*errorStack).stack.frames()
+	}
+}
+
 func BenchmarkFormat(b *testing.B) {
 	err := New("test error").With("key", "value")
 
-	for b.Loop() {
+	for i := 0; i < b.N; i++ {
 		Format(err)
 	}
 }
@@ -35,7 +94,7 @@ func BenchmarkFormat(b *testing.B) {
 func BenchmarkFormatJson(b *testing.B) {
 	err := New("test error").With("key", "value")
 
-	for b.Loop() {
+	for i := 0; i < b.N; i++ {
 		FormatJson(err)
 	}
 }
@@ -43,7 +102,84 @@ func BenchmarkFormatJson(b *testing.B) {
 func BenchmarkFormatColorized(b *testing.B) {
 	err := New("test error").With("key", "value")
 
-	for b.Loop() {
+	for i := 0; i < b.N; i++ {
 		FormatColorized(err)
 	}
 }
+
+// largeError returns an error with a deep stack, many fields and a few wrap layers
+func largeError(depth int) Error {
+	if depth > 0 {
+		return largeError(depth - 1)
+	}
+
+	err := New("test error")
+	for i := 0; i < 50; i++ {
+		err = err.With("key"+strconv.Itoa(i), i)
+	}
+
+	return Wrap(Wrap(err, "layer 1"), "layer 2")
+}
+
+func BenchmarkFormatLarge(b *testing.B) {
+	b.ReportAllocs()
+	err := largeError(30)
+
+	for i := 0; i < b.N; i++ {
+		Format(err)
+	}
+}
+
+func BenchmarkSprintfLarge(b *testing.B) {
+	b.ReportAllocs()
+	err := largeError(30)
+
+	for i := 0; i < b.N; i++ {
+		fmt.Fprintf(io.Discard, "%v", err)
+	}
+}
+
+func BenchmarkSprintfJsonLarge(b *testing.B) {
+	b.ReportAllocs()
+	err := largeError(30)
+
+	for i := 0; i < b.N; i++ {
+		fmt.Fprintf(io.Discard, "%#v", err)
+	}
+}
+
+func BenchmarkSprintfColorizedLarge(b *testing.B) {
+	b.ReportAllocs()
+	err := largeError(30)
+
+	for i := 0; i < b.N; i++ {
+		fmt.Fprintf(io.Discard, "%+v", err)
+	}
+}
+
+func BenchmarkWriteTextLarge(b *testing.B) {
+	b.ReportAllocs()
+	err := largeError(30)
+
+	for i := 0; i < b.N; i++ {
+		_ = WriteText(io.Discard, err)
+	}
+}
+
+func BenchmarkWriteJSONLarge(b *testing.B) {
+	b.ReportAllocs()
+	err := largeError(30)
+
+	for i := 0; i < b.N; i++ {
+		_ = WriteJSON(io.Discard, err)
+	}
+}
+
+func BenchmarkWriteColorizedLarge(b *testing.B) {
+	b.ReportAllocs()
+	err := largeError(30)
+
+	for i := 0; i < b.N; i++ {
+		_ = WriteColorized(io.Discard, err)
+	}
+}
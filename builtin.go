@@ -3,6 +3,7 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -12,13 +13,30 @@ var (
 	// It is useful to skip the runtime stack trace when you want to get the error message
 	// without the runtime stack trace
 	//
-	// It is true by default
+	// It is true by default, setting it to false keeps every frame matching the include
+	// and exclude rules of the StackConfig
+	//
+	// Deprecated: Setting it is not safe for concurrent use. Use SetStackConfig with
+	// KeepRuntime and KeepTesting instead.
 	SkipRuntimeStackTrace = true
 )
 
-// Is represents builtin errors.Is
+// Is reports whether any error in the chain of err matches target
+//
+// The chain of err is walked through every wrapped layer, every joined branch
+// and every error wrapped by other packages. An error in the chain matches:
+//
+//   - a target created by New, Errorf or Wrap, if it has the same root cause message.
+//     This is the message equality of the previous versions.
+//   - a target created by Sentinel, if it is the sentinel or wraps it (identity).
+//   - a target created by Define, if it is the sentinel, wraps it, or carries its code.
+//   - any other target, as errors.Is matches it (equality or an Is method).
 func Is(err, target error) bool {
-	return errors.Is(Unwrap(err), Unwrap(target))
+	if err == nil || target == nil {
+		return err == target
+	}
+
+	return newMatcher(target).match(err)
 }
 
 // As represents builtin errors.As
@@ -39,6 +57,21 @@ func Unwrap(err error) error {
 	return err
 }
 
+// CauseOf returns the error wrapped by the innermost error created by this package in the chain of err
+//
+// The cause is the error given to Wrap, e.g. the *fs.PathError of errors.Wrap(err, "open"),
+// not the errors that one wraps. Joined errors are walked depth-first. It returns nil if
+// the errors created by this package wrap no other error, and err itself if no error in
+// the chain is created by this package.
+func CauseOf(err error) error {
+	cause, found := causeOf(err)
+	if !found {
+		return err
+	}
+
+	return cause
+}
+
 type errorString struct {
 	message string
 }
@@ -84,6 +117,11 @@ func Wrapf(err error, format string, args ...any) Error {
 }
 
 // Errorf creates a formatted error, supporting '%w' verb for error wrapping
+//
+// If the format contains more than one '%w' verb, the returned Error implements
+// the Unwrap() []error method which returns every wrapped error, in the order
+// they appear in the arguments. The operands of '%w' which are nil or not errors
+// are not wrapped, and Errorf returns nil if none of them is wrapped.
 func Errorf(format string, args ...any) Error {
 	return errorf(NewTemplate(), format, args...)
 }
@@ -93,30 +131,105 @@ func errorf(template Template, format string, args ...any) Error {
 		return newError(format, 2, template)
 	}
 
-	// Check if args contains error types and format string contains %w
-	if strings.Contains(format, "%w") {
-		before, _, _ := strings.Cut(format, "%w")
-		idx := strings.Count(before, "%")
-		if len(args) < idx {
-			return New("errors: Errorf format contains more than one '%w' verb")
+	// Replace %w with %s and collect the errors to wrap
+	format, idx := wrapVerbs(format)
+
+	var errs []error
+	for _, i := range idx {
+		if i >= len(args) {
+			continue
+		}
+
+		if err, ok := args[i].(error); ok && err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(idx) != 0 && len(errs) == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf(format, args...)
+
+	switch len(errs) {
+	case 0:
+		return newError(message, 2, template)
+	case 1:
+		return wrap(errs[0], message, 2, true, template)
+	default:
+		return wrapJoin(errs, message, 2, template)
+	}
+}
+
+// wrapVerbs replaces every '%w' verb of the format with '%s',
+// and returns the indexes of the arguments of the replaced verbs
+func wrapVerbs(format string) (string, []int) {
+	var (
+		buf []byte
+		idx []int
+		arg int
+	)
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
 		}
 
-		if err, ok := args[idx].(error); ok {
-			// Replace %w with %v and don't pass error to formatting arguments
-			newFormat := strings.Replace(format, "%w", "%s", 1)
-			format = newFormat
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0", format[j]) >= 0 {
+			j++
+		}
 
-			if err == nil {
-				return newError(fmt.Sprintf(format, args...), 2, template)
-			} else {
-				return wrap(err, fmt.Sprintf(format, args...), 2, true, template)
+		// width and precision, with explicit argument indexes and '*'
+	width:
+		for j < len(format) {
+			switch c := format[j]; {
+			case c == '[':
+				end := strings.IndexByte(format[j:], ']')
+				if end < 0 {
+					j = len(format)
+					continue
+				}
+
+				if n, err := strconv.Atoi(format[j+1 : j+end]); err == nil && n > 0 {
+					arg = n - 1
+				}
+				j += end + 1
+				continue
+			case c == '*':
+				arg++
+			case c == '.' || '0' <= c && c <= '9':
+			default:
+				break width
 			}
-		} else {
-			return nil
+			j++
 		}
+
+		if j >= len(format) {
+			break
+		}
+
+		switch format[j] {
+		case '%':
+		case 'w':
+			if buf == nil {
+				buf = []byte(format)
+			}
+			buf[j] = 's'
+			idx = append(idx, arg)
+			arg++
+		default:
+			arg++
+		}
+
+		i = j
 	}
 
-	return newError(fmt.Sprintf(format, args...), 2, template)
+	if buf == nil {
+		return format, idx
+	}
+
+	return string(buf), idx
 }
 
 func replaceFormatError(format string, args ...any) string {
@@ -180,24 +293,89 @@ func replaceFormatError(format string, args ...any) string {
 	return b.String()
 }
 
-func newError(text string, ignoreCallStackCount int, tp ...Template) Error {
-	stack := getStack(ignoreCallStackCount)
-	lastCaller := frame{}
-	if len(stack) != 0 {
-		lastCaller = stack[0]
+// causeOf returns the cause of err, see CauseOf,
+// found reports whether an error created by this package is in the chain
+func causeOf(err error) (cause error, found bool) {
+	switch e := err.(type) {
+	case nil:
+		return nil, false
+	case *errorStack:
+		if e == nil {
+			return nil, false
+		}
+
+		if _, ok := e.cause.(errorString); ok || e.cause == nil {
+			return nil, true
+		}
+
+		return causeOrSelf(e.cause), true
+	case *joinError:
+		if e == nil {
+			return nil, false
+		}
+
+		cause, _ = causeOfAny(e.errs)
+		return cause, true
+	case *PanicError:
+		if inner := e.Unwrap(); inner != nil {
+			return causeOrSelf(inner), true
+		}
+
+		return nil, true
+	case interface{ Unwrap() []error }:
+		return causeOfAny(e.Unwrap())
+	case unwrap:
+		return causeOf(e.Unwrap())
+	}
+
+	return nil, false
+}
+
+// causeOrSelf returns the cause of err if an error created by this package is in its chain, or err itself
+func causeOrSelf(err error) error {
+	if cause, found := causeOf(err); found {
+		return cause
 	}
 
+	return err
+}
+
+// causeOfAny returns the first cause found in the errors, see causeOf
+func causeOfAny(errs []error) (cause error, found bool) {
+	for _, err := range errs {
+		c, ok := causeOf(err)
+		if ok && c != nil {
+			return c, true
+		}
+
+		found = found || ok
+	}
+
+	return nil, found
+}
+
+func newError(text string, ignoreCallStackCount int, tp ...Template) Error {
 	template := NewTemplate()
 	if len(tp) != 0 {
 		template = tp[0]
 	}
 
+	stack := captureStack(template.stackConfig(), ignoreCallStackCount)
+
+	var attrs []attr
+	if len(template.attr) != 0 {
+		attrs = redactAttrs(template.redaction, template.Attrs(stack.caller()))
+	}
+
 	return &errorStack{
 		message:    text,
+		code:       template.code,
 		cause:      errorString{message: text},
-		lastCaller: lastCaller,
 		stack:      stack,
-		attr:       template.Attrs(lastCaller),
+		attr:       attrs,
+		class:      template.class,
+		retryAfter: template.retryAfter,
+		redaction:  template.redaction,
 	}
 }
 
@@ -207,25 +385,31 @@ func wrap(err error, message string, ignoreCallStackCount int, combineStack bool
 	}
 
 	var (
-		msg        string
-		attrs      []attr
-		tempAttrs  []attr
-		lastCaller frame
-		cause      = err
-		stack      = getStack(ignoreCallStackCount)
-		ignore     = combineStack
-		template   = NewTemplate()
+		msg       string
+		attrs     []attr
+		tempAttrs []attr
+		cause     = err
+		ignore    = combineStack
+		template  = NewTemplate()
 	)
 
-	if len(stack) != 0 {
-		lastCaller = stack[0]
-	}
-
 	if len(tp) != 0 {
 		template = tp[0]
-		tempAttrs = template.Attrs(lastCaller)
 	}
 
+	stack := captureStack(template.stackConfig(), ignoreCallStackCount)
+
+	if err, ok := err.(*errorStack); ok {
+		stack.inner = err.stack
+	}
+
+	if len(template.attr) != 0 {
+		tempAttrs = redactAttrs(template.redaction, template.Attrs(stack.caller()))
+	}
+
+	code := template.code
+	redaction := template.redaction
+
 	if message == "" {
 		msg = err.Error()
 	} else {
@@ -238,21 +422,48 @@ func wrap(err error, message string, ignoreCallStackCount int, combineStack bool
 
 	if err, ok := err.(*errorStack); ok {
 		cause = err.cause
+		if code == "" {
+			code = err.code
+		}
+		if redaction == nil {
+			redaction = err.redaction
+		}
 		attrs = make([]attr, 0, len(err.attr)+len(tempAttrs))
 		attrs = append(attrs, err.attr...)
-
-		if len(err.stack) > len(stack) {
-			stack = err.stack[:]
-		}
 	}
 
 	attrs = append(attrs, tempAttrs...)
 
 	return &errorStack{
 		message:    msg,
+		code:       code,
 		cause:      cause,
-		lastCaller: lastCaller,
-		stack:      stack[:],
+		wrapped:    err,
+		stack:      stack,
+		attr:       attrs,
+		class:      template.class,
+		retryAfter: template.retryAfter,
+		redaction:  redaction,
+	}
+}
+
+// wrapJoin wraps the errors with the message, the returned Error unwraps to every error
+func wrapJoin(errs []error, message string, ignoreCallStackCount int, template Template) Error {
+	stack := captureStack(template.stackConfig(), ignoreCallStackCount)
+
+	var attrs []attr
+	if len(template.attr) != 0 {
+		attrs = redactAttrs(template.redaction, template.Attrs(stack.caller()))
+	}
+
+	return &joinError{
+		message:    message,
+		errs:       errs,
+		code:       template.code,
+		stack:      stack,
 		attr:       attrs,
+		class:      template.class,
+		retryAfter: template.retryAfter,
+		redaction:  template.redaction,
 	}
 }
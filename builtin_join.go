@@ -6,6 +6,7 @@ package errors
 
 import (
 	"strings"
+	"time"
 )
 
 // Join returns an error that wraps the given errors.
@@ -16,7 +17,30 @@ import (
 // between each string.
 //
 // A non-nil error returned by Join implements the Unwrap() []error method.
+//
+// The returned error formats every joined error as an indexed branch in the text,
+// JSON and colorized formats, use Combine to attach fields and codes to it.
 func Join(errs ...error) error {
+	e := join(errs, 1)
+	if e == nil {
+		return nil
+	}
+	return e
+}
+
+// Combine is like Join, but it returns an Error which supports fields and codes
+//
+//	err := errors.Combine(errA, errB).With("batch", 2)
+func Combine(errs ...error) Error {
+	e := join(errs, 1)
+	if e == nil {
+		return nil
+	}
+	return e
+}
+
+// join joins the non-nil errs, it returns nil if every value in errs is nil
+func join(errs []error, skip int) *joinError {
 	n := 0
 	for _, err := range errs {
 		if err != nil {
@@ -27,7 +51,8 @@ func Join(errs ...error) error {
 		return nil
 	}
 	e := &joinError{
-		errs: make([]error, 0, n),
+		errs:  make([]error, 0, n),
+		stack: captureStack(loadStackConfig(), skip),
 	}
 	for _, err := range errs {
 		if err != nil {
@@ -38,29 +63,65 @@ func Join(errs ...error) error {
 }
 
 type joinError struct {
-	errs []error
+	message string
+	errs    []error
+	code    Code
+	stack   *stack
+	attr    []attr
+
+	// paths are the paths of the errs collected by a Collector, nil for other joins
+	paths []string
+
+	// class and retryAfter classify whether the error is worth retrying
+	class      Class
+	retryAfter time.Duration
+
+	// redaction is the Redaction of the Template which created the error
+	redaction *Redaction
 }
 
 func (e *joinError) Error() string {
+	if e.message != "" {
+		return e.message
+	}
+
 	switch len(e.errs) {
 	case 0:
 		return ""
 	case 1:
-		return e.errs[0].Error()
+		return e.branchMessage(0)
 	default:
 		buf := stringBuilderPool.Get().(*strings.Builder)
 		defer stringBuilderPool.Put(buf)
 		buf.Reset()
 
-		buf.WriteString(e.errs[0].Error())
-		for _, err := range e.errs[1:] {
+		buf.WriteString(e.branchMessage(0))
+		for i := range e.errs[1:] {
 			buf.WriteByte('\n')
-			buf.WriteString(err.Error())
+			buf.WriteString(e.branchMessage(i + 1))
 		}
 		return buf.String()
 	}
 }
 
+// branchMessage returns the message of the i-th joined error, prefixed with its path
+func (e *joinError) branchMessage(i int) string {
+	if path := e.path(i); path != "" {
+		return path + ": " + e.errs[i].Error()
+	}
+
+	return e.errs[i].Error()
+}
+
+// path returns the path of the i-th joined error
+func (e *joinError) path(i int) string {
+	if i < len(e.paths) {
+		return e.paths[i]
+	}
+
+	return ""
+}
+
 func (e *joinError) Unwrap() []error {
 	return e.errs
 }
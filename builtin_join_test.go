@@ -1,7 +1,11 @@
 package errors
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
+
+	"github.com/yanun0323/errors/internal/colorize"
 )
 
 func TestJoin(t *testing.T) {
@@ -25,3 +29,115 @@ func TestJoin(t *testing.T) {
 		t.Errorf("expected 'test error\ntest error 2', got '%s'", errs.Error())
 	}
 }
+
+func TestJoinNil(t *testing.T) {
+	if err := Join(nil, nil); err != nil {
+		t.Errorf("expected nil, got '%v'", err)
+	}
+
+	if err := Combine(nil, nil); err != nil {
+		t.Errorf("expected nil, got '%v'", err)
+	}
+}
+
+func TestJoinWith(t *testing.T) {
+	err := WithCode(Combine(New("test error"), New("test error 2")).With("k1", "v1"), "join.failed")
+
+	joined, ok := err.(*joinError)
+	if !ok {
+		t.Fatalf("expected *joinError, got %T", err)
+	}
+
+	if len(joined.attr) != 1 || joined.attr[0].field() != (Field{"TestJoinWith", "k1", "v1"}) {
+		t.Errorf("expected field k1 attached in TestJoinWith, got %v", joined.attr)
+	}
+
+	if code := CodeOf(err); code != "join.failed" {
+		t.Errorf("expected code 'join.failed', got '%s'", code)
+	}
+
+	if err.Error() != "test error\ntest error 2" {
+		t.Errorf("expected 'test error\ntest error 2', got '%s'", err.Error())
+	}
+}
+
+func TestJoinFormat(t *testing.T) {
+	err := Combine(
+		New("branch 0").With("k0", "v0"),
+		Join(New("branch 1.0"), &OtherError{msg: "branch 1.1"}),
+	).With("k1", "v1")
+
+	text := Format(err)
+	for _, s := range []string{
+		"\nerror:\n    branch 0\n    branch 1.0\n    branch 1.1\n",
+		"field:\n    k1: v1\n",
+		"join:\n    [0]:\n        error:\n            branch 0\n",
+		"                k0: v0\n",
+		"    [1]:\n        error:\n            branch 1.0\n            branch 1.1\n",
+		"        join:\n            [0]:\n                error:\n                    branch 1.0\n",
+		"            [1]:\n                error:\n                    branch 1.1\n",
+	} {
+		if !containsString(text, s) {
+			t.Errorf("expected text format to contain '%s', got '%s'", s, text)
+		}
+	}
+
+	if fmt.Sprintf("%v", err) != text {
+		t.Errorf("expected '%%v' to be the text format, got '%v'", err)
+	}
+
+	if fmt.Sprintf("%s", err) != err.Error() {
+		t.Errorf("expected '%%s' to be the message, got '%s'", err)
+	}
+
+	colorized := colorize.ResetString(FormatColorized(err))
+	for _, s := range []string{
+		"[join]\n    [0]\n        [error] branch 0\n",
+		"    [1]\n        [error] branch 1.0\n",
+		"        [join]\n            [0]\n                [error] branch 1.0\n",
+		"            [1]\n                [error] branch 1.1\n",
+	} {
+		if !containsString(colorized, s) {
+			t.Errorf("expected colorized format to contain '%s', got '%s'", s, colorized)
+		}
+	}
+}
+
+func TestJoinJSON(t *testing.T) {
+	origin := Combine(
+		New("branch 0").With("k0", "v0"),
+		Join(New("branch 1.0"), &OtherError{msg: "branch 1.1"}),
+	).With("k1", "v1")
+
+	var v struct {
+		Error string `json:"error"`
+		Join  []struct {
+			Error string `json:"error"`
+			Field []attr `json:"field"`
+			Join  []struct {
+				Error string `json:"error"`
+			} `json:"join"`
+		} `json:"join"`
+	}
+
+	if err := json.Unmarshal([]byte(FormatJson(origin)), &v); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	if len(v.Join) != 2 || len(v.Join[0].Field) != 1 || len(v.Join[1].Join) != 2 || v.Join[1].Join[1].Error != "branch 1.1" {
+		t.Errorf("expected joined errors in json format, got %+v", v)
+	}
+
+	err, e := FromJSON([]byte(FormatJson(origin)))
+	if e != nil {
+		t.Fatalf("FromJSON error: %v", e)
+	}
+
+	if FormatJson(err) != FormatJson(origin) {
+		t.Errorf("expected the same json format, got '%s', expected '%s'", FormatJson(err), FormatJson(origin))
+	}
+
+	if !Is(err, New("branch 1.0")) {
+		t.Errorf("expected rebuilt join to match a nested branch")
+	}
+}
@@ -1,7 +1,9 @@
 package errors
 
 import (
+	"context"
 	"fmt"
+	"io/fs"
 	"testing"
 )
 
@@ -83,3 +85,29 @@ func TestReplaceFormatError(t *testing.T) {
 		})
 	}
 }
+
+func TestCauseOf(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "/not/exist", Err: fs.ErrNotExist}
+
+	testCases := []struct {
+		desc     string
+		err      error
+		expected error
+	}{
+		{"nil", nil, nil},
+		{"new", New("failed"), nil},
+		{"foreign", context.Canceled, context.Canceled},
+		{"wrapped", Wrap(pathErr, "open"), pathErr},
+		{"wrapped twice", Wrap(Wrap(pathErr, "open"), "load"), pathErr},
+		{"foreign wrapper", Wrap(fmt.Errorf("load: %w", Wrap(pathErr, "open")), "start"), pathErr},
+		{"foreign wrapper of new", fmt.Errorf("load: %w", New("failed")), nil},
+		{"joined", Join(New("first"), Wrap(context.DeadlineExceeded), Wrap(pathErr)), context.DeadlineExceeded},
+		{"panic", WithCode(&PanicError{Value: pathErr}, "panic"), pathErr},
+	}
+
+	for _, tc := range testCases {
+		if cause := CauseOf(tc.err); cause != tc.expected {
+			t.Errorf("%s: expected cause %v, got %v", tc.desc, tc.expected, cause)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package errors
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/yanun0323/errors/internal/colorize"
+)
+
+// layer is a single layer of the wrap chain of an error
+type layer struct {
+	message string
+	code    Code
+	caller  frame
+	attr    []attr
+}
+
+// layerJSON is the JSON representation of a layer
+type layerJSON struct {
+	Error  string `json:"error"`
+	Code   Code   `json:"code,omitempty"`
+	Caller *frame `json:"caller,omitempty"`
+	Field  []attr `json:"field,omitempty"`
+}
+
+// chain returns the wrap layers of the error, from the outermost to the innermost
+//
+// Every layer keeps the message, the frame where it was created or wrapped and
+// the fields added at that layer. Errors wrapped from other packages are added
+// as layers with their message only.
+func (e *errorStack) chain() []layer {
+	var layers []layer
+
+	var err error = e
+	for err != nil {
+		es, ok := err.(*errorStack)
+		if !ok {
+			layers = append(layers, layer{message: err.Error()})
+			if u, ok := err.(unwrap); ok {
+				err = u.Unwrap()
+				continue
+			}
+			break
+		}
+
+		if es == nil {
+			break
+		}
+
+		l := layer{
+			message: es.message,
+			code:    es.code,
+			caller:  es.lastCaller(),
+			attr:    es.attr,
+		}
+
+		if inner, ok := es.wrapped.(*errorStack); ok && inner != nil && len(inner.attr) <= len(es.attr) {
+			l.attr = es.attr[len(inner.attr):]
+		}
+
+		layers = append(layers, l)
+		err = es.wrapped
+	}
+
+	return layers
+}
+
+func (l layer) toJSON() layerJSON {
+	v := layerJSON{
+		Error: l.message,
+		Code:  l.code,
+		Field: l.attr,
+	}
+
+	if l.caller != (frame{}) {
+		caller := l.caller
+		v.Caller = &caller
+	}
+
+	return v
+}
+
+// chainFromJSON rebuilds the wrap chain from its JSON representation, from the innermost layer
+func chainFromJSON(layers []layerJSON) *errorStack {
+	var (
+		wrapped *errorStack
+		attrs   []attr
+	)
+
+	for i := len(layers) - 1; i >= 0; i-- {
+		l := layers[i]
+
+		var frames []frame
+		if l.Caller != nil {
+			frames = []frame{*l.Caller}
+		}
+
+		attrs = append(attrs[:len(attrs):len(attrs)], l.Field...)
+
+		e := &errorStack{
+			message: l.Error,
+			code:    l.Code,
+			stack:   newResolvedStack(frames),
+			attr:    attrs,
+		}
+
+		if wrapped != nil {
+			e.wrapped = wrapped
+		}
+
+		wrapped = e
+	}
+
+	return wrapped
+}
+
+// writeChainText writes the chain section of the text format
+func writeChainText(buf textWriter, layers []layer, full bool) {
+	buf.WriteString("chain:\n")
+	for i, l := range layers {
+		buf.WriteString(_tab)
+		buf.WriteByte('[')
+		buf.WriteString(strconv.Itoa(i))
+		buf.WriteString("] ")
+		buf.WriteString(l.message)
+		buf.WriteByte('\n')
+
+		if l.code != "" {
+			buf.WriteString(_tab)
+			buf.WriteString(_tab)
+			buf.WriteString("code: ")
+			buf.WriteString(string(l.code))
+			buf.WriteByte('\n')
+		}
+
+		if l.caller != (frame{}) {
+			buf.WriteString(_tab)
+			buf.WriteString(_tab)
+			l.caller.writeText(buf, full)
+			buf.WriteByte('\n')
+		}
+
+		for _, a := range l.attr {
+			buf.WriteString(_tab)
+			buf.WriteString(_tab)
+			buf.WriteString(a.Key)
+			buf.WriteString(": ")
+			fmt.Fprintf(buf, "%+v", a.Value)
+			buf.WriteByte('\n')
+		}
+	}
+}
+
+// writeChainColorized writes the chain section of the colorized format
+func writeChainColorized(buf textWriter, t *Theme, layers []layer, full bool) {
+	colorize.WriteString(buf, t.Section, "[chain]")
+	buf.WriteByte('\n')
+	for i, l := range layers {
+		buf.WriteString(_tab)
+		colorize.WriteString(buf, t.Index, "[", strconv.Itoa(i), "] ")
+		buf.WriteString(l.message)
+		buf.WriteByte('\n')
+
+		if l.code != "" {
+			buf.WriteString(_tab)
+			buf.WriteString(_tab)
+			colorize.WriteString(buf, t.Code, "[code] ")
+			buf.WriteString(string(l.code))
+			buf.WriteByte('\n')
+		}
+
+		if l.caller != (frame{}) {
+			buf.WriteString(_tab)
+			buf.WriteString(_tab)
+			l.caller.writeColorized(buf, t.Function, t.File, full)
+			buf.WriteByte('\n')
+		}
+
+		for _, a := range l.attr {
+			buf.WriteString(_tab)
+			buf.WriteString(_tab)
+			colorize.WriteString(buf, t.Key, "[", a.Key, "] ")
+			writeValueColorized(buf, t.Value, a.Value)
+		}
+	}
+}
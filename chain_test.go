@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func chainRoot() error {
+	return New("root").With("r1", "rv1")
+}
+
+func chainMiddle() error {
+	return WithCode(Wrap(chainRoot(), "middle"), "chain.middle").With("m1", "mv1")
+}
+
+func TestChain(t *testing.T) {
+	err := Errorf("outer: %w", chainMiddle()).With("o1", "ov1").(*errorStack)
+
+	layers := err.chain()
+	if len(layers) != 3 {
+		t.Fatalf("Expected 3 layers, got %d", len(layers))
+	}
+
+	expected := []struct {
+		message  string
+		function string
+		key      string
+	}{
+		{"outer: middle, err: root", "TestChain", "o1"},
+		{"middle, err: root", "chainMiddle", "m1"},
+		{"root", "chainRoot", "r1"},
+	}
+
+	for i, e := range expected {
+		l := layers[i]
+		if l.message != e.message {
+			t.Errorf("Expected layer %d message '%s', got '%s'", i, e.message, l.message)
+		}
+
+		if l.caller.Function != e.function {
+			t.Errorf("Expected layer %d caller '%s', got '%s'", i, e.function, l.caller.Function)
+		}
+
+		if len(l.attr) != 1 || l.attr[0].Key != e.key {
+			t.Errorf("Expected layer %d field '%s', got %v", i, e.key, l.attr)
+		}
+	}
+
+	if layers[1].code != "chain.middle" {
+		t.Errorf("Expected layer 1 code 'chain.middle', got '%s'", layers[1].code)
+	}
+}
+
+func TestChainForeign(t *testing.T) {
+	err := Wrap(&foreignError{message: "foreign", err: &OtherError{msg: "other"}}, "wrapped").(*errorStack)
+
+	layers := err.chain()
+	if len(layers) != 3 {
+		t.Fatalf("Expected 3 layers, got %d", len(layers))
+	}
+
+	if layers[1].message != "foreign" || layers[2].message != "other" {
+		t.Errorf("Expected foreign layers, got %v", layers)
+	}
+
+	if layers[1].caller != (frame{}) {
+		t.Errorf("Expected no caller of foreign layer, got %v", layers[1].caller)
+	}
+}
+
+func TestFormatChain(t *testing.T) {
+	err := Errorf("outer: %w", chainMiddle())
+
+	text := Format(err)
+	for _, s := range []string{
+		"chain:\n    [0] outer: middle, err: root\n",
+		"    [1] middle, err: root\n        code: chain.middle\n",
+		"in chainMiddle\n        m1: mv1\n    [2] root\n",
+		"in chainRoot\n        r1: rv1\n",
+	} {
+		if !strings.Contains(text, s) {
+			t.Errorf("Expected text format to contain '%s', got '%s'", s, text)
+		}
+	}
+
+	if text := Format(New("root")); strings.Contains(text, "chain:") {
+		t.Errorf("Expected no chain of an unwrapped error, got '%s'", text)
+	}
+
+	var v struct {
+		Chain []layerJSON `json:"chain"`
+	}
+	if e := json.Unmarshal([]byte(FormatJson(err)), &v); e != nil {
+		t.Fatalf("unmarshal error: %v", e)
+	}
+
+	if len(v.Chain) != 3 || v.Chain[1].Code != "chain.middle" || v.Chain[2].Caller.Function != "chainRoot" {
+		t.Errorf("Expected chain in json format, got %+v", v.Chain)
+	}
+}
+
+func TestFromJSONChain(t *testing.T) {
+	origin := Errorf("outer: %w", chainMiddle()).With("o1", "ov1")
+
+	err, e := FromJSON([]byte(FormatJson(origin)))
+	if e != nil {
+		t.Fatalf("FromJSON error: %v", e)
+	}
+
+	if Format(err) != Format(origin) {
+		t.Errorf("Expected the same text format, got '%s', expected '%s'", Format(err), Format(origin))
+	}
+
+	if FormatJson(err) != FormatJson(origin) {
+		t.Errorf("Expected the same json format, got '%s', expected '%s'", FormatJson(err), FormatJson(origin))
+	}
+}
@@ -0,0 +1,180 @@
+package errors
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Class classifies whether an error is worth retrying, the classes can be combined with '|'
+//
+//	err := errors.WithClass(errors.New("service unavailable"), errors.Temporary)
+//	errors.IsRetryable(err) // true
+type Class uint8
+
+const (
+	// Retryable marks an error which can be retried
+	Retryable Class = 1 << iota
+
+	// Temporary marks an error of a temporary condition, it is retryable
+	Temporary
+
+	// Timeout marks an error of a timeout, it is retryable
+	Timeout
+)
+
+var _classNames = []string{"retryable", "temporary", "timeout"}
+
+// String returns the names of the classes joined by '|', e.g. "temporary|timeout"
+func (c Class) String() string {
+	names := make([]string, 0, len(_classNames))
+	for i, name := range _classNames {
+		if c&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, "|")
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (c Class) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (c *Class) UnmarshalText(text []byte) error {
+	*c = 0
+	if len(text) == 0 {
+		return nil
+	}
+
+	for _, name := range strings.Split(string(text), "|") {
+		i := slices.Index(_classNames, name)
+		if i < 0 {
+			return fmt.Errorf("errors: unknown class %q", name)
+		}
+
+		*c |= 1 << i
+	}
+
+	return nil
+}
+
+// WithClass returns a copy of err classified with the given Class, added to its current Class,
+// an error not created by this package is wrapped first. It returns nil if err is nil.
+func WithClass(err error, class Class) Error {
+	return annotate(err, func(_ *Code, c *Class, _ *time.Duration) {
+		*c |= class
+	})
+}
+
+// WithRetryAfter returns a copy of err which is Retryable after the given duration,
+// an error not created by this package is wrapped first. It returns nil if err is nil.
+//
+//	err := errors.WithRetryAfter(errors.New("rate limited"), time.Second)
+func WithRetryAfter(err error, d time.Duration) Error {
+	return annotate(err, func(_ *Code, c *Class, retryAfter *time.Duration) {
+		*c |= Retryable
+		*retryAfter = d
+	})
+}
+
+// ClassOf returns the classes found in the error chain of err
+//
+// The chain is walked depth-first, including every branch of joined errors.
+// The errors implementing Timeout() bool or Temporary() bool, like net.Error and
+// context.DeadlineExceeded, are classified by them.
+func ClassOf(err error) Class {
+	class, _ := classify(err)
+	return class
+}
+
+// IsRetryable reports whether any error in the chain of err is Retryable, Temporary or Timeout
+func IsRetryable(err error) bool {
+	return ClassOf(err) != 0
+}
+
+// IsTemporary reports whether any error in the chain of err is Temporary
+func IsTemporary(err error) bool {
+	return ClassOf(err)&Temporary != 0
+}
+
+// IsTimeout reports whether any error in the chain of err is Timeout
+func IsTimeout(err error) bool {
+	return ClassOf(err)&Timeout != 0
+}
+
+// RetryAfter returns the first retry-after duration found in the error chain of err
+func RetryAfter(err error) (time.Duration, bool) {
+	_, d := classify(err)
+	return d, d > 0
+}
+
+/*
+	########  ########  #### ##     ##    ###    ######## ########
+	##     ## ##     ##  ##  ##     ##   ## ##      ##    ##
+	##     ## ##     ##  ##  ##     ##  ##   ##     ##    ##
+	########  ########   ##  ##     ## ##     ##    ##    ######
+	##        ##   ##    ##   ##   ##  #########    ##    ##
+	##        ##    ##   ##    ## ##   ##     ##    ##    ##
+	##        ##     ## ####    ###    ##     ##    ##    ########
+*/
+
+// classify returns the classes and the first retry-after duration of the error chain
+func classify(err error) (Class, time.Duration) {
+	var (
+		class      Class
+		retryAfter time.Duration
+	)
+
+	for err != nil {
+		var own Class
+		var d time.Duration
+
+		switch e := err.(type) {
+		case *errorStack:
+			own, d = e.class, e.retryAfter
+		case *joinError:
+			own, d = e.class, e.retryAfter
+		default:
+			if t, ok := err.(interface{ Timeout() bool }); ok && t.Timeout() {
+				own |= Timeout
+			}
+
+			if t, ok := err.(interface{ Temporary() bool }); ok && t.Temporary() {
+				own |= Temporary
+			}
+		}
+
+		class |= own
+		if retryAfter == 0 {
+			retryAfter = d
+		}
+
+		switch u := err.(type) {
+		case *errorStack:
+			if u.wrapped != nil {
+				err = u.wrapped
+			} else {
+				err = u.Unwrap()
+			}
+		case interface{ Unwrap() []error }:
+			for _, err := range u.Unwrap() {
+				c, d := classify(err)
+				class |= c
+				if retryAfter == 0 {
+					retryAfter = d
+				}
+			}
+			return class, retryAfter
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return class, retryAfter
+		}
+	}
+
+	return class, retryAfter
+}
@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"strings"
+	"time"
+)
+
+// Code is a machine-readable error code attached to an Error.
+//
+// A code can carry its category as a dot separated prefix,
+// e.g. "auth.token_expired" is in the "auth" category.
+type Code string
+
+// Category returns the category of the code, which is the part before the first '.'.
+//
+// It returns an empty string if the code has no category.
+func (c Code) Category() string {
+	category, _, found := strings.Cut(string(c), ".")
+	if !found {
+		return ""
+	}
+
+	return category
+}
+
+// String returns the code as a string
+func (c Code) String() string {
+	return string(c)
+}
+
+// CodeOf returns the first non-empty Code found in the error chain of err.
+//
+// The chain is walked depth-first, including every branch of joined errors.
+// It returns an empty Code if no error in the chain carries a code.
+func CodeOf(err error) Code {
+	for err != nil {
+		switch e := err.(type) {
+		case *errorStack:
+			if e.code != "" {
+				return e.code
+			}
+		case *joinError:
+			if e.code != "" {
+				return e.code
+			}
+		}
+
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, err := range u.Unwrap() {
+				if code := CodeOf(err); code != "" {
+					return code
+				}
+			}
+			return ""
+		case unwrap:
+			err = u.Unwrap()
+		default:
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// WithCode returns a copy of err with the given code, an error not created by
+// this package is wrapped first. It returns nil if err is nil.
+//
+//	err := errors.WithCode(errors.New("user not found"), "user.not_found")
+func WithCode(err error, code Code) Error {
+	return annotate(err, func(c *Code, _ *Class, _ *time.Duration) {
+		*c = code
+	})
+}
+
+/*
+	########  ########  #### ##     ##    ###    ######## ########
+	##     ## ##     ##  ##  ##     ##   ## ##      ##    ##
+	##     ## ##     ##  ##  ##     ##  ##   ##     ##    ##
+	########  ########   ##  ##     ## ##     ##    ##    ######
+	##        ##   ##    ##   ##   ##  #########    ##    ##
+	##        ##    ##   ##    ## ##   ##     ##    ##    ##
+	##        ##     ## ####    ###    ##     ##    ##    ########
+*/
+
+// annotate returns a copy of err with the code and the classification updated by fn,
+// an error not created by this package is wrapped at the caller of the exported function
+func annotate(err error, fn func(code *Code, class *Class, retryAfter *time.Duration)) Error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *errorStack:
+		if e == nil {
+			return nil
+		}
+
+		c := *e
+		fn(&c.code, &c.class, &c.retryAfter)
+		return &c
+	case *joinError:
+		if e == nil {
+			return nil
+		}
+
+		c := *e
+		fn(&c.code, &c.class, &c.retryAfter)
+		return &c
+	default:
+		return annotate(wrap(err, "", 2, false), fn)
+	}
+}
@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yanun0323/errors/internal/colorize"
+)
+
+func TestCodeCategory(t *testing.T) {
+	testCases := []struct {
+		code     Code
+		category string
+	}{
+		{"auth.token_expired", "auth"},
+		{"auth.token.expired", "auth"},
+		{"not_found", ""},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.code.Category(); got != tc.category {
+			t.Errorf("expected category of '%s' to be '%s', got '%s'", tc.code, tc.category, got)
+		}
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	err := WithCode(New("test error"), "user.not_found").With("k1", "v1")
+	if code := CodeOf(err); code != "user.not_found" {
+		t.Errorf("expected code 'user.not_found', got '%s'", code)
+	}
+
+	if code := CodeOf(Wrap(err, "wrapped")); code != "user.not_found" {
+		t.Errorf("expected wrapped code 'user.not_found', got '%s'", code)
+	}
+
+	if code := CodeOf(Errorf("errorf: %w", err)); code != "user.not_found" {
+		t.Errorf("expected errorf code 'user.not_found', got '%s'", code)
+	}
+
+	if code := CodeOf(WithCode(Wrap(err), "user.invalid")); code != "user.invalid" {
+		t.Errorf("expected overridden code 'user.invalid', got '%s'", code)
+	}
+
+	if code := CodeOf(Join(New("other"), &foreignError{message: "foreign", err: err})); code != "user.not_found" {
+		t.Errorf("expected joined code 'user.not_found', got '%s'", code)
+	}
+
+	if code := CodeOf(New("test error")); code != "" {
+		t.Errorf("expected empty code, got '%s'", code)
+	}
+
+	if code := CodeOf(nil); code != "" {
+		t.Errorf("expected empty code, got '%s'", code)
+	}
+}
+
+func TestTemplateWithCode(t *testing.T) {
+	tpl := NewTemplate("k1", "v1").WithCode("db.timeout").With("k2", 2)
+
+	if code := CodeOf(tpl.New("test")); code != "db.timeout" {
+		t.Errorf("expected code 'db.timeout', got '%s'", code)
+	}
+
+	if code := CodeOf(tpl.Errorf("test %d", 1)); code != "db.timeout" {
+		t.Errorf("expected code 'db.timeout', got '%s'", code)
+	}
+
+	if code := CodeOf(tpl.Wrap(WithCode(New("test"), "inner"))); code != "db.timeout" {
+		t.Errorf("expected template code to override inner code, got '%s'", code)
+	}
+
+	if code := CodeOf(NewTemplate().Wrap(WithCode(New("test"), "inner"))); code != "inner" {
+		t.Errorf("expected inner code, got '%s'", code)
+	}
+}
+
+func TestFormatCode(t *testing.T) {
+	err := WithCode(New("test error"), "user.not_found")
+
+	if f := Format(err); !strings.Contains(f, "code:\n    user.not_found\n") {
+		t.Errorf("expected code in text format, got '%s'", f)
+	}
+
+	if f := FormatJson(err); !strings.Contains(f, `"code": "user.not_found"`) {
+		t.Errorf("expected code in json format, got '%s'", f)
+	}
+
+	if f := colorize.ResetString(FormatColorized(err)); !strings.Contains(f, "[code] user.not_found\n") {
+		t.Errorf("expected code in colorized format, got '%s'", f)
+	}
+
+	if f := Format(New("test error")); strings.Contains(f, "code:") {
+		t.Errorf("expected no code in text format, got '%s'", f)
+	}
+}
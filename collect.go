@@ -0,0 +1,194 @@
+package errors
+
+import (
+	"strings"
+	"sync"
+)
+
+// Collector accumulates the errors of a validation, keyed by paths like "user.address.zip"
+//
+// The zero value is ready to use, and it is safe for concurrent use. ErrorOrNil
+// returns the collected errors as one Error created like Join, whose message
+// lists every error prefixed with its path, and whose JSON maps every path to
+// its message. Use ErrorsAt to query the errors of a path.
+//
+//	var c errors.Collector
+//	c.AddIf(u.Name == "", "name", "is required")
+//
+//	addr := c.Scope("address")
+//	addr.AddIf(!validZip(u.Zip), "zip", "invalid zip code %q", u.Zip)
+//
+//	return c.ErrorOrNil() // "name: is required\naddress.zip: invalid zip code "x""
+type Collector struct {
+	prefix string
+	list   *collected
+	once   sync.Once
+}
+
+// collected is the list of errors shared by a Collector and its scopes
+type collected struct {
+	mu    sync.Mutex
+	errs  []error
+	paths []string
+}
+
+// Scope returns a Collector which adds its errors to c, prefixing their paths with path
+func (c *Collector) Scope(path string) *Collector {
+	return &Collector{
+		prefix: joinPath(c.prefix, path),
+		list:   c.collected(),
+	}
+}
+
+// Add adds the error with its path, a nil error is ignored
+//
+// The errors of an Error returned by another Collector are added one by one,
+// with their paths prefixed with path.
+func (c *Collector) Add(path string, err error) {
+	if err == nil {
+		return
+	}
+
+	path = joinPath(c.prefix, path)
+	list := c.collected()
+
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	if e, ok := err.(*joinError); ok && e.paths != nil {
+		for i, err := range e.errs {
+			list.errs = append(list.errs, err)
+			list.paths = append(list.paths, joinPath(path, e.path(i)))
+		}
+
+		return
+	}
+
+	list.errs = append(list.errs, err)
+	list.paths = append(list.paths, path)
+}
+
+// Addf adds an error created like Errorf with its path
+func (c *Collector) Addf(path string, format string, args ...any) {
+	c.Add(path, errorf(NewTemplate(), format, args...))
+}
+
+// AddIf adds an error created like Errorf with its path if cond is true
+func (c *Collector) AddIf(cond bool, path string, format string, args ...any) {
+	if !cond {
+		return
+	}
+
+	c.Add(path, errorf(NewTemplate(), format, args...))
+}
+
+// Len returns the number of the collected errors, including the errors of the scopes
+func (c *Collector) Len() int {
+	list := c.collected()
+
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	return len(list.errs)
+}
+
+// ErrorOrNil returns the collected errors as one Error, or nil if no error was collected
+//
+// The collected errors of every scope are returned, regardless of the Collector
+// it is called on.
+func (c *Collector) ErrorOrNil() Error {
+	list := c.collected()
+
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	if len(list.errs) == 0 {
+		return nil
+	}
+
+	return &joinError{
+		errs:  append([]error(nil), list.errs...),
+		paths: append([]string(nil), list.paths...),
+		stack: captureStack(loadStackConfig(), 0),
+	}
+}
+
+// ErrorsAt returns the errors collected by a Collector at the path or under it,
+// e.g. "user.address" matches "user.address" and "user.address.zip"
+//
+// The error chain of err is walked until the Error returned by a Collector is found.
+// An empty path matches every collected error.
+func ErrorsAt(err error, path string) []error {
+	e := findCollected(err)
+	if e == nil {
+		return nil
+	}
+
+	var errs []error
+	for i, err := range e.errs {
+		p := e.path(i)
+		if path == "" || p == path || strings.HasPrefix(p, path+".") {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+/*
+	########  ########  #### ##     ##    ###    ######## ########
+	##     ## ##     ##  ##  ##     ##   ## ##      ##    ##
+	##     ## ##     ##  ##  ##     ##  ##   ##     ##    ##
+	########  ########   ##  ##     ## ##     ##    ##    ######
+	##        ##   ##    ##   ##   ##  #########    ##    ##
+	##        ##    ##   ##    ## ##   ##     ##    ##    ##
+	##        ##     ## ####    ###    ##     ##    ##    ########
+*/
+
+// collected returns the list of c, the list of the zero value is created on first use
+func (c *Collector) collected() *collected {
+	c.once.Do(func() {
+		if c.list == nil {
+			c.list = &collected{}
+		}
+	})
+
+	return c.list
+}
+
+// findCollected returns the first Error returned by a Collector in the error chain of err
+func findCollected(err error) *joinError {
+	for err != nil {
+		if e, ok := err.(*joinError); ok && e.paths != nil {
+			return e
+		}
+
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, err := range u.Unwrap() {
+				if e := findCollected(err); e != nil {
+					return e
+				}
+			}
+			return nil
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// joinPath joins the paths with '.', empty paths are skipped
+func joinPath(prefix, path string) string {
+	switch {
+	case prefix == "":
+		return path
+	case path == "":
+		return prefix
+	}
+
+	return prefix + "." + path
+}
@@ -0,0 +1,99 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var errRequired = Define("validation.required", "is required")
+
+func validateAddress(zip string) Error {
+	var c Collector
+	c.AddIf(len(zip) != 5, "zip", "invalid zip code %q", zip)
+	c.AddIf(zip == "", "zip", "%w", errRequired)
+
+	return c.ErrorOrNil()
+}
+
+func TestCollector(t *testing.T) {
+	var c Collector
+	if c.Len() != 0 || c.ErrorOrNil() != nil {
+		t.Fatal("Expected no error of an empty collector")
+	}
+
+	c.Add("name", nil)
+	c.Add("name", errRequired)
+	c.AddIf(false, "age", "must be positive")
+	c.Addf("age", "must be positive, got %d", -1)
+
+	user := c.Scope("user")
+	user.Add("address", validateAddress(""))
+
+	if c.Len() != 4 || user.Len() != 4 {
+		t.Fatalf("Expected 4 errors, got %d, %d", c.Len(), user.Len())
+	}
+
+	err := Wrap(c.ErrorOrNil(), "validate")
+	expected := "validate, err: name: is required\nage: must be positive, got -1\nuser.address.zip: invalid zip code \"\"\nuser.address.zip: is required"
+	if err.Error() != expected {
+		t.Errorf("Expected message '%s', got '%s'", expected, err.Error())
+	}
+
+	if !Is(err, errRequired) || CodeOf(err) != "validation.required" {
+		t.Error("Expected collected errors to match the sentinel")
+	}
+
+	if errs := ErrorsAt(err, "user.address"); len(errs) != 2 || !Is(errs[1], errRequired) {
+		t.Errorf("Expected 2 errors under 'user.address', got %v", errs)
+	}
+
+	if errs := ErrorsAt(err, "user.add"); len(errs) != 0 {
+		t.Errorf("Expected no error of a partial path, got %v", errs)
+	}
+
+	if errs := ErrorsAt(err, ""); len(errs) != 4 {
+		t.Errorf("Expected every error of an empty path, got %v", errs)
+	}
+
+	if errs := ErrorsAt(New("failed"), "name"); errs != nil {
+		t.Errorf("Expected no error without collector, got %v", errs)
+	}
+
+	if text := Format(c.ErrorOrNil()); !strings.Contains(text, "[2] user.address.zip:") || !strings.Contains(text, "in TestCollector") {
+		t.Errorf("Expected paths in text format, got '%s'", text)
+	}
+}
+
+func TestCollectorJSON(t *testing.T) {
+	var c Collector
+	c.Addf("name", "is required")
+	c.Scope("address").Addf("zip", "invalid")
+	c.Scope("address").Addf("zip", "too short")
+
+	err := c.ErrorOrNil()
+
+	var v struct {
+		Paths map[string]string `json:"paths"`
+	}
+	if e := json.Unmarshal([]byte(FormatJson(err)), &v); e != nil {
+		t.Fatalf("Expected valid JSON, got %v", e)
+	}
+
+	if len(v.Paths) != 2 || v.Paths["name"] != "is required" || v.Paths["address.zip"] != "invalid; too short" {
+		t.Errorf("Expected path to message map, got %v", v.Paths)
+	}
+
+	rebuilt, e := FromJSON([]byte(FormatJson(err)))
+	if e != nil {
+		t.Fatalf("Expected rebuilt error, got %v", e)
+	}
+
+	if rebuilt.Error() != err.Error() {
+		t.Errorf("Expected rebuilt message '%s', got '%s'", err.Error(), rebuilt.Error())
+	}
+
+	if errs := ErrorsAt(rebuilt, "address.zip"); len(errs) != 2 || errs[1].Error() != "too short" {
+		t.Errorf("Expected rebuilt paths, got %v", errs)
+	}
+}
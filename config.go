@@ -0,0 +1,247 @@
+package errors
+
+import (
+	"path"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	_defaultMaxDepth = 32
+)
+
+// StackConfig configures how call stacks are captured and which frames are reported.
+//
+// The zero value captures up to 32 frames and drops the frames of the Go runtime,
+// the standard library and the testing package.
+type StackConfig struct {
+	// MaxDepth is the maximum number of frames reported, 32 is used if it is not positive.
+	//
+	// It limits the frames kept by the other rules, not the frames before they are filtered.
+	MaxDepth int
+
+	// KeepRuntime keeps the frames of the Go runtime and the standard library,
+	// except the testing package, and the package initialization functions.
+	KeepRuntime bool
+
+	// KeepTesting keeps the frames of the testing package.
+	KeepTesting bool
+
+	// Include keeps only the frames whose package path has one of the prefixes.
+	// All frames are kept if it is empty.
+	Include []string
+
+	// Exclude drops the frames whose package path has one of the prefixes.
+	Exclude []string
+
+	// IncludePattern keeps only the frames whose full function name matches the pattern.
+	IncludePattern *regexp.Regexp
+
+	// ExcludePattern drops the frames whose full function name matches the pattern.
+	ExcludePattern *regexp.Regexp
+
+	// RelativePaths reports file paths relative to the module containing them,
+	// e.g. "internal/failed/failed.go" instead of an absolute path.
+	//
+	// Files of packages outside any known module are reported with their package path,
+	// e.g. "net/http/server.go".
+	RelativePaths bool
+}
+
+var (
+	_stackConfig atomic.Pointer[StackConfig]
+)
+
+// DefaultStackConfig returns the default StackConfig
+func DefaultStackConfig() StackConfig {
+	return StackConfig{
+		MaxDepth: _defaultMaxDepth,
+	}
+}
+
+// SetStackConfig sets the package-level StackConfig used by errors created
+// without a Template overriding it.
+//
+// It is safe to call concurrently with creating and formatting errors.
+// Errors keep the config which was active when they were created.
+func SetStackConfig(cfg StackConfig) {
+	_stackConfig.Store(cfg.clone())
+}
+
+// CurrentStackConfig returns the package-level StackConfig
+func CurrentStackConfig() StackConfig {
+	return *loadStackConfig().clone()
+}
+
+func loadStackConfig() *StackConfig {
+	if cfg := _stackConfig.Load(); cfg != nil {
+		return cfg
+	}
+
+	cfg := DefaultStackConfig()
+	_stackConfig.CompareAndSwap(nil, &cfg)
+	return _stackConfig.Load()
+}
+
+func (cfg StackConfig) clone() *StackConfig {
+	cfg.Include = slices.Clone(cfg.Include)
+	cfg.Exclude = slices.Clone(cfg.Exclude)
+	return &cfg
+}
+
+func (cfg *StackConfig) maxDepth() int {
+	if cfg.MaxDepth <= 0 {
+		return _defaultMaxDepth
+	}
+
+	return cfg.MaxDepth
+}
+
+// keep reports whether the frame should be reported,
+// skipRuntime is the SkipRuntimeStackTrace when the stack was captured
+func (cfg *StackConfig) keep(f runtime.Frame, skipRuntime bool) bool {
+	pkg := packagePath(f.Function)
+
+	if !skipRuntime {
+		return cfg.match(f.Function, pkg)
+	}
+
+	if isTesting(pkg) {
+		if !cfg.KeepTesting {
+			return false
+		}
+	} else if !cfg.KeepRuntime && (isStandard(pkg, dotlessModules()) || strings.HasSuffix(f.Function, ".init")) {
+		return false
+	}
+
+	return cfg.match(f.Function, pkg)
+}
+
+// match reports whether the frame matches the include and exclude rules
+func (cfg *StackConfig) match(function, pkg string) bool {
+	if len(cfg.Include) != 0 && !hasPathPrefix(pkg, cfg.Include) {
+		return false
+	}
+
+	if hasPathPrefix(pkg, cfg.Exclude) {
+		return false
+	}
+
+	if cfg.IncludePattern != nil && !cfg.IncludePattern.MatchString(function) {
+		return false
+	}
+
+	if cfg.ExcludePattern != nil && cfg.ExcludePattern.MatchString(function) {
+		return false
+	}
+
+	return true
+}
+
+// file returns the file path of the frame to report
+func (cfg *StackConfig) file(file, pkg string) string {
+	if !cfg.RelativePaths {
+		return file
+	}
+
+	return relativePath(file, pkg)
+}
+
+// packagePath returns the package path of a full function name,
+// e.g. "github.com/yanun0323/errors" of "github.com/yanun0323/errors.(*errorStack).Error"
+func packagePath(function string) string {
+	if i := strings.IndexByte(function, '['); i >= 0 {
+		function = function[:i]
+	}
+
+	slash := strings.LastIndexByte(function, '/') + 1
+	dot := strings.IndexByte(function[slash:], '.')
+	if dot < 0 {
+		return function
+	}
+
+	return function[:slash+dot]
+}
+
+func hasPathPrefix(pkg string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if pkg == prefix || strings.HasPrefix(pkg, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isTesting(pkg string) bool {
+	return pkg == "testing" || strings.HasPrefix(pkg, "testing/")
+}
+
+// isStandard reports whether the package is part of the Go runtime or the standard library,
+// which are the packages without a dot in the first element of their path, except main
+// and the packages of the modules, e.g. the packages of "module myapp"
+func isStandard(pkg string, modules []string) bool {
+	first, _, _ := strings.Cut(pkg, "/")
+	return first != "main" && !strings.Contains(first, ".") && !hasPathPrefix(pkg, modules)
+}
+
+// dotlessModules returns the paths of the main module and the dependencies of the build
+// without a dot in their first element, their packages look like the standard library
+var dotlessModules = sync.OnceValue(func() []string {
+	var paths []string
+	for _, p := range modulePaths() {
+		if first, _, _ := strings.Cut(p, "/"); !strings.Contains(first, ".") {
+			paths = append(paths, p)
+		}
+	}
+
+	return paths
+})
+
+var modulePaths = sync.OnceValue(func() []string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	paths := make([]string, 0, len(info.Deps)+1)
+	if info.Main.Path != "" {
+		paths = append(paths, info.Main.Path)
+	}
+
+	for _, dep := range info.Deps {
+		paths = append(paths, dep.Path)
+	}
+
+	return paths
+})
+
+// relativePath returns the file path relative to the module containing the package
+func relativePath(file, pkg string) string {
+	base := path.Base(file)
+	if pkg == "main" || pkg == "" {
+		return base
+	}
+
+	module := ""
+	for _, p := range modulePaths() {
+		if len(p) > len(module) && (pkg == p || strings.HasPrefix(pkg, p+"/")) {
+			module = p
+		}
+	}
+
+	if module == "" {
+		return pkg + "/" + base
+	}
+
+	if dir := strings.TrimPrefix(strings.TrimPrefix(pkg, module), "/"); dir != "" {
+		return dir + "/" + base
+	}
+
+	return base
+}
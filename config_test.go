@@ -0,0 +1,242 @@
+package errors
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func stackFunctions(err Error) []string {
+	frames := err.(*errorStack).stack.frames()
+	functions := make([]string, 0, len(frames))
+	for _, f := range frames {
+		functions = append(functions, f.Function)
+	}
+
+	return functions
+}
+
+func TestStackConfigDefault(t *testing.T) {
+	functions := stackFunctions(New("test error"))
+	if len(functions) != 1 || functions[0] != "TestStackConfigDefault" {
+		t.Errorf("Expected only TestStackConfigDefault, got %v", functions)
+	}
+}
+
+func TestStackConfigKeepTesting(t *testing.T) {
+	defer SetStackConfig(CurrentStackConfig())
+	SetStackConfig(StackConfig{KeepTesting: true})
+
+	functions := stackFunctions(New("test error"))
+	if len(functions) != 2 || functions[1] != "tRunner" {
+		t.Errorf("Expected TestStackConfigKeepTesting and tRunner, got %v", functions)
+	}
+}
+
+func TestStackConfigKeepRuntime(t *testing.T) {
+	defer SetStackConfig(CurrentStackConfig())
+	SetStackConfig(StackConfig{KeepRuntime: true})
+
+	functions := stackFunctions(New("test error"))
+	if len(functions) != 2 || functions[1] != "goexit" {
+		t.Errorf("Expected TestStackConfigKeepRuntime and goexit, got %v", functions)
+	}
+}
+
+func TestStackConfigMaxDepth(t *testing.T) {
+	defer SetStackConfig(CurrentStackConfig())
+	SetStackConfig(StackConfig{MaxDepth: 1, KeepTesting: true, KeepRuntime: true})
+
+	functions := stackFunctions(New("test error"))
+	if len(functions) != 1 {
+		t.Errorf("Expected 1 frame, got %v", functions)
+	}
+
+	SetStackConfig(StackConfig{MaxDepth: 1, KeepTesting: true, ExcludePattern: regexp.MustCompile(`TestStackConfigMaxDepth$`)})
+	if functions := stackFunctions(New("test error")); len(functions) != 1 || functions[0] != "tRunner" {
+		t.Errorf("Expected the first kept frame tRunner, got %v", functions)
+	}
+}
+
+func TestStackConfigSkipRuntimeStackTrace(t *testing.T) {
+	SkipRuntimeStackTrace = false
+	err := New("test error")
+	SkipRuntimeStackTrace = true
+
+	if functions := stackFunctions(err); len(functions) < 3 || functions[1] != "tRunner" {
+		t.Errorf("Expected the runtime frames kept at creation, got %v", functions)
+	}
+}
+
+func TestStackConfigIncludeExclude(t *testing.T) {
+	defer SetStackConfig(CurrentStackConfig())
+
+	SetStackConfig(StackConfig{KeepTesting: true, Include: []string{"testing"}})
+	if functions := stackFunctions(New("test error")); len(functions) != 1 || functions[0] != "tRunner" {
+		t.Errorf("Expected only tRunner, got %v", functions)
+	}
+
+	SetStackConfig(StackConfig{Exclude: []string{"github.com/yanun0323/errors"}})
+	if functions := stackFunctions(New("test error")); len(functions) != 0 {
+		t.Errorf("Expected no frame, got %v", functions)
+	}
+
+	SetStackConfig(StackConfig{KeepTesting: true, ExcludePattern: regexp.MustCompile(`\.tRunner$`)})
+	if functions := stackFunctions(New("test error")); len(functions) != 1 || functions[0] != "TestStackConfigIncludeExclude" {
+		t.Errorf("Expected only TestStackConfigIncludeExclude, got %v", functions)
+	}
+
+	SetStackConfig(StackConfig{KeepTesting: true, IncludePattern: regexp.MustCompile(`^testing\.`)})
+	if functions := stackFunctions(New("test error")); len(functions) != 1 || functions[0] != "tRunner" {
+		t.Errorf("Expected only tRunner, got %v", functions)
+	}
+}
+
+func TestStackConfigRelativePaths(t *testing.T) {
+	defer SetStackConfig(CurrentStackConfig())
+	SetStackConfig(StackConfig{RelativePaths: true, KeepTesting: true})
+
+	frames := New("test error").(*errorStack).stack.frames()
+	if len(frames) != 2 {
+		t.Fatalf("Expected 2 frames, got %v", frames)
+	}
+
+	if frames[0].File != "config_test.go" {
+		t.Errorf("Expected config_test.go, got %s", frames[0].File)
+	}
+
+	if frames[1].File != "testing/testing.go" {
+		t.Errorf("Expected testing/testing.go, got %s", frames[1].File)
+	}
+}
+
+func TestTemplateWithStackConfig(t *testing.T) {
+	tpl := NewTemplate("k1", "v1").WithStackConfig(StackConfig{KeepTesting: true})
+
+	if functions := stackFunctions(tpl.New("test error")); len(functions) != 2 || functions[1] != "tRunner" {
+		t.Errorf("Expected TestTemplateWithStackConfig and tRunner, got %v", functions)
+	}
+
+	if functions := stackFunctions(tpl.Wrap(New("test error"))); len(functions) != 2 || functions[1] != "tRunner" {
+		t.Errorf("Expected TestTemplateWithStackConfig and tRunner, got %v", functions)
+	}
+
+	if functions := stackFunctions(tpl.With("k2", "v2").New("test error")); len(functions) != 2 {
+		t.Errorf("Expected the stack config to be kept by With, got %v", functions)
+	}
+
+	if functions := stackFunctions(New("test error")); len(functions) != 1 {
+		t.Errorf("Expected the package-level config to be unchanged, got %v", functions)
+	}
+}
+
+func TestPackagePath(t *testing.T) {
+	testCases := []struct {
+		function string
+		pkg      string
+	}{
+		{"github.com/yanun0323/errors.(*errorStack).Error", "github.com/yanun0323/errors"},
+		{"github.com/yanun0323/errors/internal/failed.Failed.Error", "github.com/yanun0323/errors/internal/failed"},
+		{"github.com/yanun0323/errors.TestNew.func1", "github.com/yanun0323/errors"},
+		{"github.com/yanun0323/errors.Map[...]", "github.com/yanun0323/errors"},
+		{"main.main", "main"},
+		{"runtime.goexit", "runtime"},
+	}
+
+	for _, tc := range testCases {
+		if got := packagePath(tc.function); got != tc.pkg {
+			t.Errorf("Expected package path of '%s' to be '%s', got '%s'", tc.function, tc.pkg, got)
+		}
+	}
+}
+
+func TestIsStandard(t *testing.T) {
+	testCases := []struct {
+		pkg      string
+		modules  []string
+		expected bool
+	}{
+		{"runtime", nil, true},
+		{"net/http", []string{"myapp"}, true},
+		{"myapp/svc", nil, true},
+		{"myapp/svc", []string{"myapp"}, false},
+		{"myapp", []string{"myapp"}, false},
+		{"myapplication/svc", []string{"myapp"}, true},
+		{"main", nil, false},
+		{"github.com/yanun0323/errors", nil, false},
+	}
+
+	for _, tc := range testCases {
+		if got := isStandard(tc.pkg, tc.modules); got != tc.expected {
+			t.Errorf("Expected isStandard of '%s' with modules %v to be %t, got %t", tc.pkg, tc.modules, tc.expected, got)
+		}
+	}
+}
+
+func TestStackConfigDotlessModule(t *testing.T) {
+	goBin, err := exec.LookPath("go")
+	if err != nil || testing.Short() {
+		t.Skip("go command is not available")
+	}
+
+	root, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module myapp\n\ngo 1.21\n\nrequire github.com/yanun0323/errors v0.0.0\n\nreplace github.com/yanun0323/errors => " + root + "\n",
+		"svc/svc.go": `package svc
+
+import "github.com/yanun0323/errors"
+
+type Service struct{}
+
+func (*Service) Handle() errors.Error {
+	return errors.New("failed").With("user_id", 1)
+}
+`,
+		"main.go": `package main
+
+import (
+	"fmt"
+
+	"github.com/yanun0323/errors"
+	"myapp/svc"
+)
+
+func main() {
+	err := (&svc.Service{}).Handle()
+	fmt.Println(errors.Fields(err)[0].Function)
+	for _, f := range errors.StackOf(err) {
+		fmt.Println(f.FullFunction)
+	}
+}
+`,
+	}
+
+	for name, content := range files {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Expected the program to run, got %v: %s", err, out)
+	}
+
+	expected := "Handle\nmyapp/svc.(*Service).Handle\nmain.main\n"
+	if string(out) != expected {
+		t.Errorf("Expected the frames of the dotless module '%s', got '%s'", expected, out)
+	}
+}
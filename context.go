@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// contextKey is the key of the Template stored in a context.Context
+type contextKey struct{}
+
+var _contextFields = struct {
+	sync.RWMutex
+	fns []func(ctx context.Context) []any
+}{}
+
+// WithContext returns a copy of ctx carrying the fields, in addition to the fields already carried by ctx
+//
+// The errors created by NewCtx, WrapCtx and ErrorfCtx attach the fields carried by the context.
+//
+//	ctx = errors.WithContext(ctx, "request_id", requestID, "tenant", tenant)
+//	err := errors.NewCtx(ctx, "user not found") // with request_id and tenant
+func WithContext(ctx context.Context, args ...any) context.Context {
+	return ContextWithTemplate(ctx, TemplateFromContext(ctx).With(args...))
+}
+
+// ContextWithTemplate returns a copy of ctx carrying the Template
+func ContextWithTemplate(ctx context.Context, t Template) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// TemplateFromContext returns the Template carried by ctx, or an empty Template if there is none
+func TemplateFromContext(ctx context.Context) Template {
+	if ctx == nil {
+		return NewTemplate()
+	}
+
+	if t, ok := ctx.Value(contextKey{}).(Template); ok {
+		return t
+	}
+
+	return NewTemplate()
+}
+
+// RegisterContextFields registers a function which extracts request-scoped fields from a context,
+// e.g. the trace and span IDs. The fields are attached to the errors created by NewCtx, WrapCtx and ErrorfCtx.
+//
+// The function returns the fields in the same form as With.
+func RegisterContextFields(fn func(ctx context.Context) []any) {
+	_contextFields.Lock()
+	defer _contextFields.Unlock()
+
+	_contextFields.fns = append(_contextFields.fns, fn)
+}
+
+// NewCtx creates a new error with the Template and the fields carried by ctx
+func NewCtx(ctx context.Context, text string) Error {
+	return newError(text, 1, contextTemplate(ctx))
+}
+
+// WrapCtx wraps an error with the Template and the fields carried by ctx, see Wrap
+func WrapCtx(ctx context.Context, err error, args ...any) Error {
+	var message string
+	if len(args) != 0 {
+		message = fmt.Sprint(args...)
+	}
+
+	return wrap(err, message, 1, false, contextTemplate(ctx))
+}
+
+// ErrorfCtx creates a formatted error with the Template and the fields carried by ctx, see Errorf
+func ErrorfCtx(ctx context.Context, format string, args ...any) Error {
+	return errorf(contextTemplate(ctx), format, args...)
+}
+
+// contextTemplate returns the Template carried by ctx with the fields of the registered functions
+func contextTemplate(ctx context.Context) Template {
+	t := TemplateFromContext(ctx)
+	if ctx == nil {
+		return t
+	}
+
+	_contextFields.RLock()
+	defer _contextFields.RUnlock()
+
+	for _, fn := range _contextFields.fns {
+		if args := fn(ctx); len(args) != 0 {
+			t = t.With(args...)
+		}
+	}
+
+	return t
+}
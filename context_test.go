@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"context"
+	"testing"
+)
+
+type traceKey struct{}
+
+func init() {
+	RegisterContextFields(func(ctx context.Context) []any {
+		if traceID, ok := ctx.Value(traceKey{}).(string); ok {
+			return []any{"trace_id", traceID}
+		}
+
+		return nil
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	ctx := WithContext(context.Background(), "request_id", "req-1")
+	ctx = WithContext(ctx, String("tenant", "acme"))
+
+	if attrs := TemplateFromContext(ctx).attr; len(attrs) != 2 {
+		t.Errorf("Expected 2 fields in the template, got %v", attrs)
+	}
+
+	if attrs := TemplateFromContext(context.Background()).attr; len(attrs) != 0 {
+		t.Errorf("Expected an empty template, got %v", attrs)
+	}
+
+	if attrs := TemplateFromContext(nil).attr; len(attrs) != 0 {
+		t.Errorf("Expected an empty template, got %v", attrs)
+	}
+}
+
+func TestContextConstructors(t *testing.T) {
+	ctx := WithContext(context.Background(), "request_id", "req-1")
+	ctx = ContextWithTemplate(ctx, TemplateFromContext(ctx).WithCode("ctx.failed"))
+	ctx = context.WithValue(ctx, traceKey{}, "trace-1")
+
+	testCases := []struct {
+		desc string
+		err  Error
+	}{
+		{"NewCtx", NewCtx(ctx, "failed")},
+		{"WrapCtx", WrapCtx(ctx, New("root"), "failed")},
+		{"ErrorfCtx", ErrorfCtx(ctx, "failed: %w", New("root"))},
+		{"ErrorfCtx multiple", ErrorfCtx(ctx, "failed: %w, %w", New("a"), New("b"))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if v, _ := Lookup(tc.err, "request_id"); v != "req-1" {
+				t.Errorf("Expected request_id 'req-1', got %v", v)
+			}
+
+			if v, _ := Lookup(tc.err, "trace_id"); v != "trace-1" {
+				t.Errorf("Expected trace_id 'trace-1', got %v", v)
+			}
+
+			if CodeOf(tc.err) != "ctx.failed" {
+				t.Errorf("Expected code 'ctx.failed', got '%s'", CodeOf(tc.err))
+			}
+
+			for _, f := range Fields(tc.err) {
+				if f.Function != "TestContextConstructors" {
+					t.Errorf("Expected fields attached in TestContextConstructors, got '%s'", f.Function)
+				}
+			}
+		})
+	}
+
+	if WrapCtx(ctx, nil) != nil {
+		t.Error("Expected nil error")
+	}
+}
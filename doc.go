@@ -35,11 +35,18 @@
 //	jsonWithStack := fmt.Sprintf("%#v", err)  // equal to errors.FormatJSON(err)
 //	colorizedWithStack := fmt.Sprintf("%+v", err)  // equal to errors.FormatColorized(err)
 //
-//	// Check if the error is a specific error
+//	// Check if the error is a specific error,
+//	// errors created by New are matched by message
 //	if errors.Is(err, errors.New("user validation failed")) {
 //		// handle the error
 //	}
 //
+//	// errors created by Sentinel are matched by identity
+//	var ErrValidation = errors.Sentinel("user validation failed")
+//	if errors.Is(err, ErrValidation) {
+//		// handle the error
+//	}
+//
 //	var validationErr AnErrorType
 //	if errors.As(err, &validationErr) {
 //		// handle the error
@@ -0,0 +1,126 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yanun0323/errors/internal/logs"
+)
+
+func TestWrapVerbs(t *testing.T) {
+	testCases := []struct {
+		format   string
+		expected string
+		idx      []int
+	}{
+		{"no verb", "no verb", nil},
+		{"%s %w", "%s %s", []int{1}},
+		{"%w, %w", "%s, %s", []int{0, 1}},
+		{"100%% %w", "100%% %s", []int{0}},
+		{"%*d %-5w %.2f %+w", "%*d %-5s %.2f %+s", []int{2, 4}},
+		{"%[2]w %[1]w %w", "%[2]s %[1]s %s", []int{1, 0, 1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			format, idx := wrapVerbs(tc.format)
+			if format != tc.expected {
+				t.Errorf("Expected format '%s', got '%s'", tc.expected, format)
+			}
+
+			if len(idx) != len(tc.idx) {
+				t.Fatalf("Expected indexes %v, got %v", tc.idx, idx)
+			}
+
+			for i := range idx {
+				if idx[i] != tc.idx[i] {
+					t.Errorf("Expected indexes %v, got %v", tc.idx, idx)
+				}
+			}
+		})
+	}
+}
+
+var (
+	errMultiFirst  = New("first")
+	errMultiSecond = New("second")
+)
+
+func TestErrorfMultipleWrap(t *testing.T) {
+	other := &OtherError{msg: "other"}
+	err := Errorf("failed: %w, %w and %w", errMultiFirst.With("k1", "v1"), Wrap(errMultiSecond, "wrapped"), other)
+
+	if err.Error() != "failed: first, wrapped, err: second and other" {
+		t.Errorf("Expected message, got '%s'", err.Error())
+	}
+
+	u, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("Expected Unwrap() []error, got %T", err)
+	}
+
+	if errs := u.Unwrap(); len(errs) != 3 {
+		t.Errorf("Expected 3 wrapped errors, got %d", len(errs))
+	}
+
+	if !Is(err, errMultiFirst) || !Is(err, errMultiSecond) {
+		t.Error("Expected error to match every wrapped error")
+	}
+
+	var target *OtherError
+	if !As(err, &target) || target.msg != "other" {
+		t.Error("Expected error to be found by As")
+	}
+
+	text := Format(err)
+	for _, s := range []string{
+		"error:\n    failed: first, wrapped, err: second and other\n",
+		"in TestErrorfMultipleWrap",
+		"join:\n    [0]:\n",
+		"    [1]:\n        error:\n            wrapped, err: second\n",
+		"    [2]:\n        error:\n            other\n",
+	} {
+		if !strings.Contains(text, s) {
+			t.Errorf("Expected text format to contain '%s', got '%s'", s, text)
+		}
+	}
+
+	attrs := err.(logs.Error).Attributes()
+	if len(attrs) != 1 || attrs[0].(attr).Key != "k1" {
+		t.Errorf("Expected the fields of every branch, got %v", attrs)
+	}
+
+	rebuilt, e := FromJSON([]byte(FormatJson(err)))
+	if e != nil {
+		t.Fatalf("FromJSON error: %v", e)
+	}
+
+	if rebuilt.Error() != err.Error() || Format(rebuilt) != Format(err) {
+		t.Errorf("Expected the same text format, got '%s', expected '%s'", Format(rebuilt), Format(err))
+	}
+}
+
+func TestTemplateErrorfMultipleWrap(t *testing.T) {
+	err := NewTemplate().WithCode("multi.failed").With("k1", "v1").Errorf("%w: %w", errMultiFirst, nil)
+	if _, ok := err.(*errorStack); !ok {
+		t.Errorf("Expected a single wrapped error, got %T", err)
+	}
+
+	err = NewTemplate().WithCode("multi.failed").With("k1", "v1").Errorf("%w: %w", errMultiFirst, errMultiSecond)
+	joined, ok := err.(*joinError)
+	if !ok {
+		t.Fatalf("Expected *joinError, got %T", err)
+	}
+
+	if CodeOf(err) != "multi.failed" {
+		t.Errorf("Expected code 'multi.failed', got '%s'", CodeOf(err))
+	}
+
+	if len(joined.attr) != 1 || joined.attr[0].field() != (Field{"TestTemplateErrorfMultipleWrap", "k1", "v1"}) {
+		t.Errorf("Expected template fields, got %v", joined.attr)
+	}
+
+	if joined.lastCaller().Function != "TestTemplateErrorfMultipleWrap" {
+		t.Errorf("Expected caller 'TestTemplateErrorfMultipleWrap', got '%s'", joined.lastCaller().Function)
+	}
+}
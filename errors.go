@@ -1,13 +1,11 @@
 package errors
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"path/filepath"
 	"runtime"
-	"strconv"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/yanun0323/errors/internal/colorize"
 )
@@ -21,15 +19,36 @@ type attr struct {
 	Function string `json:"function"`
 	Key      string `json:"key"`
 	Value    any    `json:"value"`
+
+	// fullFunction is the full name of the function, see frame.FullFunction
+	fullFunction string
+}
+
+// name returns the function name of the attribute to render,
+// the full function name if full is set and the attribute has it
+func (a attr) name(full bool) string {
+	if full && a.fullFunction != "" {
+		return a.fullFunction
+	}
+
+	return a.Function
 }
 
 // errorStack the custom error type
 type errorStack struct {
-	message    string
-	cause      error
-	lastCaller frame
-	stack      []frame
-	attr       []attr
+	message string
+	code    Code
+	cause   error
+	wrapped error
+	stack   *stack
+	attr    []attr
+
+	// class and retryAfter classify whether the error is worth retrying
+	class      Class
+	retryAfter time.Duration
+
+	// redaction is the Redaction of the Template which created the error
+	redaction *Redaction
 }
 
 /*
@@ -56,6 +75,10 @@ func (e *errorStack) Unwrap() error {
 		return nil
 	}
 
+	if _, ok := e.cause.(*PanicError); ok {
+		return e.cause
+	}
+
 	if err, ok := e.cause.(unwrap); ok {
 		return err.Unwrap()
 	}
@@ -69,28 +92,14 @@ func (e *errorStack) Unwrap() error {
 // '%v' - text format
 // '%+v' - colorized format
 // '%#v' - json format
+//
+// The formats of the '%v' verbs can be replaced by SetVerb.
 func (e *errorStack) Format(f fmt.State, c rune) {
 	if e == nil {
 		return
 	}
 
-	switch c {
-	case 'v':
-		if f.Flag('+') {
-			f.Write([]byte(e.formatColorized()))
-			return
-		}
-
-		if f.Flag('#') {
-			f.Write([]byte(e.formatJson()))
-			return
-		}
-
-		f.Write([]byte(e.formatText()))
-		return
-	}
-
-	f.Write([]byte(e.Error()))
+	formatVerb(e, f, c)
 }
 
 // With adds additional fields, supporting method chaining
@@ -99,17 +108,12 @@ func (e *errorStack) With(args ...any) Error {
 		return nil
 	}
 
-	attrs := make([]attr, 0, len(e.attr)+len(args)/2)
-	attrs = append(attrs, e.attr...)
-	attrs = append(attrs, makeArgs(e.lastCaller.Function, args...)...)
+	c := *e
+	c.attr = make([]attr, 0, len(e.attr)+len(args)/2)
+	c.attr = append(c.attr, e.attr...)
+	c.attr = append(c.attr, redactAttrs(e.redaction, makeArgs(e.lastCaller(), args...))...)
 
-	return &errorStack{
-		message:    e.message,
-		cause:      e.cause,
-		lastCaller: e.lastCaller,
-		stack:      e.stack,
-		attr:       attrs,
-	}
+	return &c
 }
 
 func (e *errorStack) WithMap(m map[string]any) Error {
@@ -117,23 +121,21 @@ func (e *errorStack) WithMap(m map[string]any) Error {
 		return nil
 	}
 
-	attrs := make([]attr, 0, len(e.attr)+len(m))
-	attrs = append(attrs, e.attr...)
+	c := *e
+	c.attr = make([]attr, 0, len(e.attr)+len(m))
+	c.attr = append(c.attr, e.attr...)
+	caller := e.lastCaller()
 	for k, v := range m {
-		attrs = append(attrs, attr{
-			Function: e.lastCaller.Function,
-			Key:      k,
-			Value:    v,
-		})
+		c.attr = append(c.attr, newAttr(caller, k, v))
 	}
+	redactAttrs(e.redaction, c.attr[len(e.attr):])
 
-	return &errorStack{
-		message:    e.message,
-		cause:      e.cause,
-		lastCaller: e.lastCaller,
-		stack:      e.stack,
-		attr:       attrs,
-	}
+	return &c
+}
+
+// lastCaller returns the frame where the error was created or wrapped
+func (e *errorStack) lastCaller() frame {
+	return e.stack.caller()
 }
 
 // String returns basic string format
@@ -151,24 +153,25 @@ func (e *errorStack) String() string {
 	##        ##     ## ####    ###    ##     ##    ##    ########
 */
 
-// formatText returns text formatted error information
-func (e *errorStack) formatText() string {
+// writeText writes text formatted error information,
+// the functions are rendered with their full names if full is set
+func (e *errorStack) writeText(buf textWriter, full bool) {
 	if e == nil {
-		return ""
+		return
 	}
 
-	buf := stringBuilderPool.Get().(*strings.Builder)
-	defer stringBuilderPool.Put(buf)
-	buf.Reset()
-
-	buf.Grow(1024)
-
-	buf.WriteByte('\n')
 	buf.WriteString("error:\n")
 	buf.WriteString(_tab)
 	buf.WriteString(e.message)
 	buf.WriteByte('\n')
 
+	if e.code != "" {
+		buf.WriteString("code:\n")
+		buf.WriteString(_tab)
+		buf.WriteString(string(e.code))
+		buf.WriteByte('\n')
+	}
+
 	if e.cause != nil {
 		buf.WriteString("cause:\n")
 		buf.WriteString(_tab)
@@ -176,18 +179,12 @@ func (e *errorStack) formatText() string {
 		buf.WriteByte('\n')
 	}
 
+	if layers := e.chain(); len(layers) > 1 {
+		writeChainText(buf, layers, full)
+	}
+
 	if len(e.attr) != 0 {
-		var (
-			attrMap       = make(map[string][]attr, 32)
-			attrFunctions = make([]string, 0, 32)
-		)
-
-		for _, a := range e.attr {
-			if _, ok := attrMap[a.Function]; !ok {
-				attrFunctions = append(attrFunctions, a.Function)
-			}
-			attrMap[a.Function] = append(attrMap[a.Function], a)
-		}
+		attrFunctions, attrMap := groupAttrs(e.attr, full)
 
 		buf.WriteString("field:\n")
 
@@ -208,96 +205,52 @@ func (e *errorStack) formatText() string {
 				buf.WriteString(a.Key)
 				buf.WriteByte(':')
 				buf.WriteByte(' ')
-				buf.WriteString(fmt.Sprintf("%+v", a.Value))
+				fmt.Fprintf(buf, "%+v", a.Value)
 				buf.WriteByte('\n')
 			}
 		}
 	}
 
-	if len(e.stack) != 0 {
-		buf.WriteString("stack:\n")
-		for _, f := range e.stack {
-			buf.WriteString(_tab)
-			buf.WriteString(f.Function)
-			buf.WriteByte(':')
-			buf.WriteByte('\n')
-			buf.WriteString(_tab)
-			buf.WriteString(_tab)
-			buf.WriteString(f.FormatText())
-			buf.WriteByte('\n')
-		}
-	}
-
-	return buf.String()
-}
-
-// formatJson returns formatJson formatted error information
-func (e *errorStack) formatJson() string {
-	if e == nil {
-		return _emptyJSONString
-	}
-
-	data := make(map[string]any, 4)
-	data["error"] = e.message
-	data["field"] = e.attr
-
-	if e.cause != nil {
-		data["cause"] = e.cause.Error()
-	}
-
-	if len(e.stack) > 0 {
-		data["stack"] = e.stack
-	}
-
-	jsonBytes, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return fmt.Sprintf(`{"error": "marshal error: %s"}`, err.Error())
-	}
-
-	return string(jsonBytes)
+	writeStackText(buf, e.stack.frames(), full)
 }
 
-// formatColorized returns colorized readable format (ANSI color codes)
-func (e *errorStack) formatColorized() string {
+// writeColorized writes colorized readable format (ANSI color codes),
+// the functions are rendered with their full names if full is set
+func (e *errorStack) writeColorized(buf textWriter, t *Theme, full bool) {
 	if e == nil {
-		return ""
+		return
 	}
 
-	buf := stringBuilderPool.Get().(*strings.Builder)
-	defer stringBuilderPool.Put(buf)
-	buf.Reset()
-	buf.Grow(1024)
-
-	buf.WriteByte('\n')
-	colorize.WriteString(buf, colorize.Red, "[error] ")
+	colorize.WriteString(buf, t.Error, "[error] ")
 	buf.WriteString(e.message)
 	buf.WriteByte('\n')
 
+	if e.code != "" {
+		colorize.WriteString(buf, t.Code, "[code] ")
+		buf.WriteString(string(e.code))
+		buf.WriteByte('\n')
+	}
+
 	if e.cause != nil {
-		colorize.WriteString(buf, colorize.Yellow, "[cause] ")
+		colorize.WriteString(buf, t.Cause, "[cause] ")
 		buf.WriteString(e.cause.Error())
 		buf.WriteByte('\n')
 	}
 
+	if layers := e.chain(); len(layers) > 1 {
+		writeChainColorized(buf, t, layers, full)
+	}
+
 	if len(e.attr) > 0 {
-		var (
-			attrMap       = make(map[string][]attr, 32)
-			attrFunctions = make([]string, 0, 32)
-		)
-		for _, a := range e.attr {
-			if _, ok := attrMap[a.Function]; !ok {
-				attrFunctions = append(attrFunctions, a.Function)
-			}
-			attrMap[a.Function] = append(attrMap[a.Function], a)
-		}
+		attrFunctions, attrMap := groupAttrs(e.attr, full)
 
-		colorize.WriteString(buf, colorize.Cyan, "[field]")
+		colorize.WriteString(buf, t.Section, "[field]")
 		buf.WriteByte('\n')
 		for _, key := range attrFunctions {
 			hasFuncName := key != ""
 			if hasFuncName {
 				buf.WriteString(_tab)
-				colorize.WriteString(buf, colorize.Blue, "[", key, "] ")
+				colorize.WriteString(buf, t.Function, "[", key, "] ")
 				buf.WriteByte('\n')
 			}
 
@@ -306,91 +259,75 @@ func (e *errorStack) formatColorized() string {
 					buf.WriteString(_tab)
 				}
 				buf.WriteString(_tab)
-				colorize.WriteString(buf, colorize.Magenta, "[", a.Key, "] ")
-				colorize.WriteString(buf, colorize.Black, fmt.Sprintf("%+v\n", a.Value))
-			}
-		}
-	}
-
-	if len(e.stack) > 0 {
-		colorize.WriteString(buf, colorize.Cyan, "[stack]")
-		buf.WriteByte('\n')
-		for _, f := range e.stack {
-			if strings.HasPrefix(f.Function, "runtime") {
-				continue
+				colorize.WriteString(buf, t.Key, "[", a.Key, "] ")
+				writeValueColorized(buf, t.Value, a.Value)
 			}
-
-			buf.WriteString(_tab)
-			buf.WriteString(f.FormatColorized(colorize.Blue, colorize.Black))
-			buf.WriteByte('\n')
 		}
 	}
 
-	return buf.String()
+	writeStackColorized(buf, t, e.stack.frames(), full)
 }
 
-// getStack captures the current call stack
-func getStack(additionalSkip ...int) []frame {
-	skip := _defaultSkip
-	if len(additionalSkip) != 0 {
-		skip += additionalSkip[0]
+// writeStackText writes the stack section of the text format
+func writeStackText(buf textWriter, frames []frame, full bool) {
+	if len(frames) == 0 {
+		return
 	}
 
-	var frames []frame
-
-	pc := make([]uintptr, 32)
-	n := runtime.Callers(skip, pc)
-
-	if n == 0 {
-		return frames
+	buf.WriteString("stack:\n")
+	for _, f := range frames {
+		buf.WriteString(_tab)
+		buf.WriteString(f.name(full))
+		buf.WriteByte(':')
+		buf.WriteByte('\n')
+		buf.WriteString(_tab)
+		buf.WriteString(_tab)
+		f.writeText(buf, full)
+		buf.WriteByte('\n')
 	}
+}
 
-	callersFrames := runtime.CallersFrames(pc)
+// writeStackColorized writes the stack section of the colorized format
+func writeStackColorized(buf textWriter, t *Theme, frames []frame, full bool) {
+	if len(frames) == 0 {
+		return
+	}
 
-	for {
-		f, more := callersFrames.Next()
-		if SkipRuntimeStackTrace && canSkip(f) {
+	colorize.WriteString(buf, t.Section, "[stack]")
+	buf.WriteByte('\n')
+	for _, f := range frames {
+		if strings.HasPrefix(f.Function, "runtime") {
 			continue
 		}
 
-		if f.Function != "" {
-			funcName := f.Function
-			span := strings.Split(funcName, "/")
-			funcName = span[len(span)-1]
-			span = strings.Split(funcName, ".")
-			funcName = span[len(span)-1]
-
-			frames = append(frames, frame{
-				File:     f.File,
-				Function: funcName,
-				Line:     strconv.Itoa(f.Line),
-			})
-		}
-
-		if !more {
-			break
-		}
+		buf.WriteString(_tab)
+		f.writeColorized(buf, t.Function, t.File, full)
+		buf.WriteByte('\n')
 	}
-
-	return frames
 }
 
-func canSkip(f runtime.Frame) bool {
-	if strings.HasSuffix(f.Function, ".init") {
-		return true
+// getStack captures the program counters of the current call stack with the package-level StackConfig,
+// the frames are resolved lazily when they are formatted
+func getStack(additionalSkip ...int) *stack {
+	skip := 1
+	if len(additionalSkip) != 0 {
+		skip += additionalSkip[0]
 	}
 
-	if strings.HasSuffix(f.Function, "tRunner") {
-		return true
-	}
+	return captureStack(loadStackConfig(), skip)
+}
 
-	if strings.Contains(f.File, string(os.PathSeparator)+filepath.Join("src", "runtime")) {
-		return true
+// captureStack captures the program counters of the current call stack with the given StackConfig
+func captureStack(cfg *StackConfig, additionalSkip int) *stack {
+	// MaxDepth limits the frames kept by the config, so the program counters of
+	// the frames to drop are captured too
+	var buf [2 * _defaultMaxDepth]uintptr
+	pc := buf[:]
+	if depth := cfg.maxDepth() + _defaultMaxDepth; depth > len(buf) {
+		pc = make([]uintptr, depth)
 	}
 
-	if strings.Contains(f.File, string(os.PathSeparator)+filepath.Join("go", "src")) {
-		return true
-	}
+	n := runtime.Callers(_defaultSkip+additionalSkip, pc)
 
-	return false
+	return newStack(slices.Clone(pc[:n]), cfg)
 }
@@ -84,6 +84,17 @@ func TestErrorfWrap(t *testing.T) {
 	expected := `
 [error] formatted error, err: root
 [cause] root
+[chain]
+    [0] formatted error, err: root
+        [TestErrorfWrap] /Users/Shared/Project/personal/go/errors/errors_test.go:74
+        [user_id] 123
+        [k1] v1
+        [k2] 2
+    [1] root
+        [causeError] /Users/Shared/Project/personal/go/errors/errors_test.go:70
+        [r1] rv1
+        [r2] 22
+    [2] root
 [field]
     [causeError] 
         [r1] rv1
@@ -121,6 +132,17 @@ func TestWrap(t *testing.T) {
 	expected := `
 [error] wrapped, err: root
 [cause] root
+[chain]
+    [0] wrapped, err: root
+        [TestWrap] /Users/Shared/Project/personal/go/errors/errors_test.go:119
+        [k1] v1
+        [k2] 2
+        [k3] 3
+    [1] root
+        [causeError] /Users/Shared/Project/personal/go/errors/errors_test.go:70
+        [r1] rv1
+        [r2] 22
+    [2] root
 [field]
     [causeError] 
         [r1] rv1
@@ -131,7 +153,7 @@ func TestWrap(t *testing.T) {
         [k3] 3
 [stack]
     [causeError] /Users/Shared/Project/personal/go/errors/errors_test.go:70
-    [TestWrap] /Users/Shared/Project/personal/go/errors/errors_test.go:108
+    [TestWrap] /Users/Shared/Project/personal/go/errors/errors_test.go:119
 `
 
 	f := FormatColorized(wrappedErr)
@@ -158,6 +180,17 @@ func TestWrapf(t *testing.T) {
 	expected := `
 [error] wrapped world, err: root
 [cause] root
+[chain]
+    [0] wrapped world, err: root
+        [TestWrapf] /Users/Shared/Project/personal/go/errors/errors_test.go:167
+        [k1] v1
+        [k2] 2
+        [k3] 3
+    [1] root
+        [causeError] /Users/Shared/Project/personal/go/errors/errors_test.go:70
+        [r1] rv1
+        [r2] 22
+    [2] root
 [field]
     [causeError] 
         [r1] rv1
@@ -168,7 +201,7 @@ func TestWrapf(t *testing.T) {
         [k3] 3
 [stack]
     [causeError] /Users/Shared/Project/personal/go/errors/errors_test.go:70
-    [TestWrapf] /Users/Shared/Project/personal/go/errors/errors_test.go:145
+    [TestWrapf] /Users/Shared/Project/personal/go/errors/errors_test.go:167
 `
 
 	f := FormatColorized(wrappedErr)
@@ -186,11 +219,11 @@ func TestWith(t *testing.T) {
 		t.Fatalf("Expected 2 attributes, got %d", len(attrs))
 	}
 
-	if attrs[0] != (attr{"TestWith", "user_id", 123}) {
+	if attrs[0].field() != (Field{"TestWith", "user_id", 123}) {
 		t.Errorf("Expected user_id=123, got %v", attrs[0])
 	}
 
-	if attrs[1] != (attr{"TestWith", "action", "create"}) {
+	if attrs[1].field() != (Field{"TestWith", "action", "create"}) {
 		t.Errorf("Expected action=create, got %v", attrs[1])
 	}
 }
@@ -287,7 +320,7 @@ func TestColorizedString(t *testing.T) {
 
 func TestStackTrace(t *testing.T) {
 	err := New("test error")
-	stack := err.(*errorStack).stack
+	stack := err.(*errorStack).stack.frames()
 
 	if len(stack) == 0 {
 		t.Error("Expected stack trace")
@@ -372,15 +405,15 @@ func TestAdditionalFields(t *testing.T) {
 		t.Fatalf("Expected 3 attributes, got %d", len(err.attr))
 	}
 
-	if err.attr[0] != (attr{"TestAdditionalFields", "user_id", 12345}) {
+	if err.attr[0].field() != (Field{"TestAdditionalFields", "user_id", 12345}) {
 		t.Errorf("Expected user_id=12345, got %v", err.attr[0])
 	}
 
-	if err.attr[1] != (attr{"TestAdditionalFields", "email", "user@example.com"}) {
+	if err.attr[1].field() != (Field{"TestAdditionalFields", "email", "user@example.com"}) {
 		t.Errorf("Expected email=user@example.com, got %v", err.attr[1])
 	}
 
-	if err.attr[2] != (attr{"TestAdditionalFields", "attempt", 3}) {
+	if err.attr[2].field() != (Field{"TestAdditionalFields", "attempt", 3}) {
 		t.Errorf("Expected attempt=3, got %v", err.attr[2])
 	}
 }
@@ -399,15 +432,15 @@ func TestChainChecking(t *testing.T) {
 		t.Fatalf("Expected 3 attributes, got %d", len(err.attr))
 	}
 
-	if err.attr[0] != (attr{"TestChainChecking", "user_id", 12345}) {
+	if err.attr[0].field() != (Field{"TestChainChecking", "user_id", 12345}) {
 		t.Errorf("Expected user_id=12345, got %v", err.attr[0])
 	}
 
-	if err.attr[1] != (attr{"TestChainChecking", "email", "user@example.com"}) {
+	if err.attr[1].field() != (Field{"TestChainChecking", "email", "user@example.com"}) {
 		t.Errorf("Expected email=user@example.com, got %v", err.attr[1])
 	}
 
-	if err.attr[2] != (attr{"TestChainChecking", "attempt", 3}) {
+	if err.attr[2].field() != (Field{"TestChainChecking", "attempt", 3}) {
 		t.Errorf("Expected attempt=3, got %v", err.attr[2])
 	}
 }
@@ -426,7 +459,7 @@ field:
         key: value
 stack:
     TestFormat:
-        /Users/Shared/Project/personal/go/errors/errors_test.go:416 in TestFormat
+        /Users/Shared/Project/personal/go/errors/errors_test.go:449 in TestFormat
 `
 
 	if formatted != expected {
@@ -443,8 +476,10 @@ func TestFormatJson(t *testing.T) {
 		With("attempt", 3)
 
 	expected := `{
-  "cause": "user validation failed",
   "error": "user validation failed",
+  "cause": {
+    "error": "user validation failed"
+  },
   "field": [
     {
       "function": "TestFormatJson",
@@ -466,7 +501,9 @@ func TestFormatJson(t *testing.T) {
     {
       "file": "/Users/Shared/Project/personal/go/errors/errors_test.go",
       "function": "TestFormatJson",
-      "line": "440"
+      "line": "473",
+      "package": "github.com/yanun0323/errors",
+      "full_function": "github.com/yanun0323/errors.TestFormatJson"
     }
   ]
 }`
@@ -493,7 +530,7 @@ func TestFormatColorized(t *testing.T) {
         [email] user@example.com
         [attempt] 3
 [stack]
-    [TestFormatColorized] /Users/Shared/Project/personal/go/errors/errors_test.go:482
+    [TestFormatColorized] /Users/Shared/Project/personal/go/errors/errors_test.go:519
 `
 
 	f := FormatColorized(err)
@@ -530,15 +567,15 @@ func TestRealWorld(t *testing.T) {
 		t.Fatalf("Expected 3 attributes, got %d", len(sErr.attr))
 	}
 
-	if sErr.attr[0] != (attr{"checkTime", "now", "2025-06-04 16:47:09"}) {
+	if sErr.attr[0].field() != (Field{"checkTime", "now", "2025-06-04 16:47:09"}) {
 		t.Errorf("Expected now=2025-06-04 16:47:09, got %v", sErr.attr[0])
 	}
 
-	if sErr.attr[1] != (attr{"validateUser", "user_id", 123}) {
+	if sErr.attr[1].field() != (Field{"validateUser", "user_id", 123}) {
 		t.Errorf("Expected user_id=123, got %v", sErr.attr[1])
 	}
 
-	if sErr.attr[2] != (attr{"validateUser", "table", "users"}) {
+	if sErr.attr[2].field() != (Field{"validateUser", "table", "users"}) {
 		t.Errorf("Expected table=users, got %v", sErr.attr[2])
 	}
 }
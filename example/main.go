@@ -14,7 +14,7 @@ var (
 )
 
 func main() {
-	ctx := context.Background()
+	ctx := errors.WithContext(context.Background(), "request_id", "req-123")
 	err := handleRequest(ctx, 0)
 
 	if err != nil {
@@ -45,7 +45,7 @@ func handleRequest(ctx context.Context, userID int) error {
 // processUser handles user-related operations
 func processUser(ctx context.Context, userID int) error {
 	if err := validateUser(userID); err != nil {
-		return errors.Errorf("user validation failed, err: %w", err).
+		return errors.ErrorfCtx(ctx, "user validation failed, err: %w", err).
 			With("func", "processUser")
 	}
 
@@ -0,0 +1,100 @@
+package errors
+
+// Field is a field attached to an error, with the function it was attached in
+type Field struct {
+	Function string `json:"function"`
+	Key      string `json:"key"`
+	Value    any    `json:"value"`
+}
+
+// Fields returns the fields attached to every error in the chain of err
+//
+// The chain is walked through every wrapped layer, every joined branch and every
+// error wrapped by other packages. The fields are returned in the order they
+// were attached: the fields of the innermost error come first, the fields of a
+// joined error come after the fields of its branches.
+func Fields(err error) []Field {
+	return appendFields(nil, err)
+}
+
+// Lookup returns the value of the field with the given key in the chain of err
+//
+// If the key is set more than once, the last attached value wins: the value of
+// the outermost layer, or the last value set in the same layer.
+func Lookup(err error, key string) (any, bool) {
+	fields := Fields(err)
+	for i := len(fields) - 1; i >= 0; i-- {
+		if fields[i].Key == key {
+			return fields[i].Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// LookupAll returns every field with the given key in the chain of err, in the order they were attached
+func LookupAll(err error, key string) []Field {
+	var result []Field
+	for _, f := range Fields(err) {
+		if f.Key == key {
+			result = append(result, f)
+		}
+	}
+
+	return result
+}
+
+func appendFields(fields []Field, err error) []Field {
+	switch e := err.(type) {
+	case nil:
+		return fields
+	case *errorStack:
+		if e == nil {
+			return fields
+		}
+
+		own := e.attr
+		if inner, ok := e.wrapped.(*errorStack); ok && inner != nil && len(inner.attr) <= len(e.attr) {
+			own = e.attr[len(inner.attr):]
+		}
+
+		return appendAttrFields(appendFields(fields, e.wrapped), own)
+	case *joinError:
+		if e == nil {
+			return fields
+		}
+
+		for _, err := range e.errs {
+			fields = appendFields(fields, err)
+		}
+
+		return appendAttrFields(fields, e.attr)
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			fields = appendFields(fields, err)
+		}
+
+		return fields
+	case unwrap:
+		return appendFields(fields, e.Unwrap())
+	}
+
+	return fields
+}
+
+func appendAttrFields(fields []Field, attrs []attr) []Field {
+	for _, a := range attrs {
+		fields = append(fields, a.field())
+	}
+
+	return fields
+}
+
+// field returns the Field of the attribute
+func (a attr) field() Field {
+	return Field{
+		Function: a.Function,
+		Key:      a.Key,
+		Value:    a.Value,
+	}
+}
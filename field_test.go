@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"testing"
+)
+
+func fieldRoot() error {
+	return New("root").With("k1", "root", "k2", 2)
+}
+
+func fieldMiddle() error {
+	return Wrap(&foreignError{message: "foreign", err: fieldRoot()}, "middle").With("k1", "middle")
+}
+
+func TestFields(t *testing.T) {
+	err := Combine(fieldMiddle(), New("branch").With("k3", 3)).With("k1", "join")
+
+	fields := Fields(err)
+	expected := []Field{
+		{"fieldRoot", "k1", "root"},
+		{"fieldRoot", "k2", 2},
+		{"fieldMiddle", "k1", "middle"},
+		{"TestFields", "k3", 3},
+		{"TestFields", "k1", "join"},
+	}
+
+	if len(fields) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, fields)
+	}
+
+	for i := range fields {
+		if fields[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected[i], fields[i])
+		}
+	}
+
+	if v, ok := Lookup(err, "k1"); !ok || v != "join" {
+		t.Errorf("Expected the outermost value 'join', got %v", v)
+	}
+
+	if v, ok := Lookup(err, "k2"); !ok || v != 2 {
+		t.Errorf("Expected 2, got %v", v)
+	}
+
+	if _, ok := Lookup(err, "missing"); ok {
+		t.Error("Expected missing key not to be found")
+	}
+
+	all := LookupAll(err, "k1")
+	if len(all) != 3 || all[0].Function != "fieldRoot" || all[1].Function != "fieldMiddle" || all[2].Function != "TestFields" {
+		t.Errorf("Expected every k1 field, got %v", all)
+	}
+}
+
+func TestFieldsPrecedence(t *testing.T) {
+	err := Wrap(New("root").With("k1", 1).With("k1", 2), "wrapped").With("k1", 3)
+	if v, _ := Lookup(err, "k1"); v != 3 {
+		t.Errorf("Expected the outermost value 3, got %v", v)
+	}
+
+	err = NewTemplate("k1", "template").Wrap(New("root").With("k1", "root"))
+	if v, _ := Lookup(err, "k1"); v != "template" {
+		t.Errorf("Expected the template value, got %v", v)
+	}
+
+	if fields := Fields(nil); len(fields) != 0 {
+		t.Errorf("Expected no fields, got %v", fields)
+	}
+
+	if fields := Fields(&OtherError{msg: "other"}); len(fields) != 0 {
+		t.Errorf("Expected no fields, got %v", fields)
+	}
+}
@@ -1,45 +1,89 @@
 package errors
 
+import (
+	"io"
+	"strings"
+)
+
 const (
 	_emptyJSONString = "{}"
 	_emptyString     = ""
 )
 
+// make the built-in formatters implement WriterFormatter
+var (
+	_ WriterFormatter = TextFormatter{}
+	_ WriterFormatter = jsonFormatter{}
+	_ WriterFormatter = ColorizedFormatter{}
+)
+
 // Format formats the error as a string
 func Format(err error) string {
-	if err == nil {
-		return _emptyString
-	}
-
-	if err, ok := err.(*errorStack); ok {
-		return err.formatText()
-	}
-
-	return err.Error()
+	return TextFormatter{}.Format(err)
 }
 
 // FormatJson formats the error as a JSON string
 func FormatJson(err error) string {
-	if err == nil {
-		return _emptyJSONString
-	}
-
-	if err, ok := err.(*errorStack); ok {
-		return err.formatJson()
-	}
-
-	return err.Error()
+	return jsonFormatter{}.Format(err)
 }
 
 // FormatColorized formats the error as a colorized string
 func FormatColorized(err error) string {
-	if err == nil {
-		return _emptyString
-	}
+	return ColorizedFormatter{}.Format(err)
+}
+
+// TextFormatter renders an error in the multi-line text format of Format,
+// the zero value is the built-in Formatter of TextFormat
+type TextFormatter struct {
+	// FullFunctionNames renders the frames and the fields with the full function name,
+	// e.g. "github.com/acme/app.(*Service).Handle" instead of "Handle".
+	FullFunctionNames bool
+}
+
+// Format implements the Formatter interface
+func (t TextFormatter) Format(err error) string {
+	return formatString(t, err)
+}
+
+// Write implements the WriterFormatter interface
+func (t TextFormatter) Write(w io.Writer, err error) error {
+	return writeFormatted(w, err, nil, t.FullFunctionNames)
+}
+
+// jsonFormatter is the built-in Formatter of JSONFormat
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(err error) string {
+	return formatString(jsonFormatter{}, err)
+}
+
+func (jsonFormatter) Write(w io.Writer, err error) error {
+	return WriteJSON(w, err)
+}
+
+// ColorizedFormatter renders an error in the colorized format of FormatColorized,
+// the zero value is the built-in Formatter of ColorizedFormat
+type ColorizedFormatter struct {
+	// FullFunctionNames renders the frames and the fields with the full function name,
+	// e.g. "github.com/acme/app.(*Service).Handle" instead of "Handle".
+	FullFunctionNames bool
+}
+
+// Format implements the Formatter interface
+func (c ColorizedFormatter) Format(err error) string {
+	return formatString(c, err)
+}
+
+// Write implements the WriterFormatter interface, the colors are chosen for w like WriteColorized
+func (c ColorizedFormatter) Write(w io.Writer, err error) error {
+	return writeFormatted(w, err, themeFor(w), c.FullFunctionNames)
+}
 
-	if err, ok := err.(*errorStack); ok {
-		return err.formatColorized()
-	}
+// formatString returns the output written by the formatter
+func formatString(f WriterFormatter, err error) string {
+	var buf strings.Builder
+	buf.Grow(1024)
 
-	return err.Error()
+	_ = f.Write(&buf, err)
+	return buf.String()
 }
@@ -0,0 +1,200 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Formatter renders an error as a string, it can be registered with RegisterFormatter
+type Formatter interface {
+	Format(err error) string
+}
+
+// WriterFormatter is a Formatter which writes the error to an io.Writer without building a string,
+// the fmt verbs of Error use Write if the formatter implements it
+type WriterFormatter interface {
+	Formatter
+	Write(w io.Writer, err error) error
+}
+
+// FormatterFunc is a function implementing Formatter
+type FormatterFunc func(err error) string
+
+// Format implements the Formatter interface
+func (f FormatterFunc) Format(err error) string {
+	return f(err)
+}
+
+// The names of the built-in formatters
+const (
+	TextFormat      = "text"
+	JSONFormat      = "json"
+	ColorizedFormat = "colorized"
+)
+
+// Verb is a fmt verb of Error rendered by a registered formatter
+type Verb string
+
+// The verbs of Error which can be rendered by any registered formatter, see SetVerb
+const (
+	VerbV      Verb = "%v"
+	VerbPlusV  Verb = "%+v"
+	VerbSharpV Verb = "%#v"
+)
+
+var (
+	_formatterMu sync.RWMutex
+	_formatters  = map[string]Formatter{
+		TextFormat:      TextFormatter{},
+		JSONFormat:      jsonFormatter{},
+		ColorizedFormat: ColorizedFormatter{},
+		LogfmtFormat:    LogfmtFormatter{},
+	}
+	_verbs = map[Verb]string{
+		VerbV:      TextFormat,
+		VerbPlusV:  ColorizedFormat,
+		VerbSharpV: JSONFormat,
+	}
+)
+
+// RegisterFormatter registers the formatter with the name, for FormatWith and SetVerb
+//
+// It panics if the formatter is nil, or if the name is empty or already registered.
+//
+//	errors.RegisterFormatter("markdown", errors.FormatterFunc(func(err error) string {
+//		return "**" + err.Error() + "**"
+//	}))
+func RegisterFormatter(name string, f Formatter) {
+	_formatterMu.Lock()
+	defer _formatterMu.Unlock()
+
+	if f == nil {
+		panic("errors: RegisterFormatter formatter is nil")
+	}
+
+	if name == "" {
+		panic("errors: RegisterFormatter name is empty")
+	}
+
+	if _, ok := _formatters[name]; ok {
+		panic("errors: RegisterFormatter called twice for formatter " + name)
+	}
+
+	_formatters[name] = f
+}
+
+// Formatters returns the sorted names of the registered formatters, including the built-ins
+func Formatters() []string {
+	_formatterMu.RLock()
+	defer _formatterMu.RUnlock()
+
+	names := make([]string, 0, len(_formatters))
+	for name := range _formatters {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// FormatWith formats the error with the registered formatter of the name,
+// it returns the message of the error if no formatter is registered with the name
+func FormatWith(err error, name string) string {
+	if err == nil {
+		return _emptyString
+	}
+
+	f, ok := lookupFormatter(name)
+	if !ok {
+		return err.Error()
+	}
+
+	return f.Format(err)
+}
+
+// SetVerb sets the registered formatter used by the verb to format an Error
+//
+// By default '%v' uses TextFormat, '%+v' uses ColorizedFormat and '%#v' uses JSONFormat.
+// The formatter must not format the error with the verb, or it recurses forever.
+//
+//	errors.SetVerb(errors.VerbV, "logfmt")
+func SetVerb(verb Verb, name string) error {
+	_formatterMu.Lock()
+	defer _formatterMu.Unlock()
+
+	if _, ok := _verbs[verb]; !ok {
+		return fmt.Errorf("errors: unsupported verb %q", verb)
+	}
+
+	if _, ok := _formatters[name]; !ok {
+		return fmt.Errorf("errors: unknown formatter %q", name)
+	}
+
+	_verbs[verb] = name
+	return nil
+}
+
+/*
+	########  ########  #### ##     ##    ###    ######## ########
+	##     ## ##     ##  ##  ##     ##   ## ##      ##    ##
+	##     ## ##     ##  ##  ##     ##  ##   ##     ##    ##
+	########  ########   ##  ##     ## ##     ##    ##    ######
+	##        ##   ##    ##   ##   ##  #########    ##    ##
+	##        ##    ##   ##    ## ##   ##     ##    ##    ##
+	##        ##     ## ####    ###    ##     ##    ##    ########
+*/
+
+// lookupFormatter returns the registered formatter of the name
+func lookupFormatter(name string) (Formatter, bool) {
+	_formatterMu.RLock()
+	defer _formatterMu.RUnlock()
+
+	f, ok := _formatters[name]
+	return f, ok
+}
+
+// unregisterFormatter removes the registered formatter of the name, for tests
+func unregisterFormatter(name string) {
+	_formatterMu.Lock()
+	defer _formatterMu.Unlock()
+
+	delete(_formatters, name)
+}
+
+// currentVerb returns the name of the formatter used by the verb
+func currentVerb(verb Verb) string {
+	_formatterMu.RLock()
+	defer _formatterMu.RUnlock()
+
+	return _verbs[verb]
+}
+
+// formatVerb implements the fmt.Formatter interface of the errors,
+// the 'v' verbs are rendered by the formatters set by SetVerb
+func formatVerb(err error, f fmt.State, c rune) {
+	if c != 'v' {
+		f.Write([]byte(err.Error()))
+		return
+	}
+
+	verb := VerbV
+	switch {
+	case f.Flag('+'):
+		verb = VerbPlusV
+	case f.Flag('#'):
+		verb = VerbSharpV
+	}
+
+	_formatterMu.RLock()
+	formatter := _formatters[_verbs[verb]]
+	_formatterMu.RUnlock()
+
+	if w, ok := formatter.(WriterFormatter); ok {
+		_ = w.Write(newStateWriter(f), err)
+		return
+	}
+
+	f.Write([]byte(formatter.Format(err)))
+}
@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFormatWith(t *testing.T) {
+	err := WithCode(New("user not found"), "user.not_found")
+
+	if FormatWith(err, TextFormat) != Format(err) || FormatWith(err, JSONFormat) != FormatJson(err) || FormatWith(err, ColorizedFormat) != FormatColorized(err) {
+		t.Error("Expected built-in formatters to match the Format functions")
+	}
+
+	if text := FormatWith(err, "unknown"); text != "user not found" {
+		t.Errorf("Expected message of an unknown formatter, got '%s'", text)
+	}
+
+	if text := FormatWith(nil, TextFormat); text != "" {
+		t.Errorf("Expected empty string of nil error, got '%s'", text)
+	}
+
+	RegisterFormatter("test.markdown", FormatterFunc(func(err error) string {
+		return "**" + err.Error() + "** `" + string(CodeOf(err)) + "`"
+	}))
+	t.Cleanup(func() { unregisterFormatter("test.markdown") })
+
+	if text := FormatWith(err, "test.markdown"); text != "**user not found** `user.not_found`" {
+		t.Errorf("Expected markdown format, got '%s'", text)
+	}
+
+	if !slices.Contains(Formatters(), "test.markdown") || !slices.Contains(Formatters(), JSONFormat) {
+		t.Errorf("Expected registered formatters, got %v", Formatters())
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected panic of a duplicate formatter")
+			}
+		}()
+
+		RegisterFormatter(TextFormat, FormatterFunc(Format))
+	}()
+}
+
+func TestSetVerb(t *testing.T) {
+	RegisterFormatter("test.upper", FormatterFunc(func(err error) string {
+		return strings.ToUpper(err.Error())
+	}))
+	t.Cleanup(func() { unregisterFormatter("test.upper") })
+
+	previous := currentVerb(VerbPlusV)
+	if err := SetVerb(VerbPlusV, "test.upper"); err != nil {
+		t.Fatalf("Expected verb set, got %v", err)
+	}
+	t.Cleanup(func() { _ = SetVerb(VerbPlusV, previous) })
+
+	err := Join(New("first"), New("second"))
+	if text := fmt.Sprintf("%+v", err); text != "FIRST\nSECOND" {
+		t.Errorf("Expected upper format of '%%+v', got '%s'", text)
+	}
+
+	if text := fmt.Sprintf("%v", err); text != Format(err) {
+		t.Errorf("Expected text format of '%%v', got '%s'", text)
+	}
+
+	if text := fmt.Sprintf("%s", err); text != "first\nsecond" {
+		t.Errorf("Expected message of '%%s', got '%s'", text)
+	}
+
+	if err := SetVerb("%d", TextFormat); err == nil {
+		t.Error("Expected error of an unsupported verb")
+	}
+
+	if err := SetVerb(VerbV, "unknown"); err == nil {
+		t.Error("Expected error of an unknown formatter")
+	}
+}
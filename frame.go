@@ -1,16 +1,208 @@
 package errors
 
 import (
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/yanun0323/errors/internal/colorize"
 )
 
+// stack is a captured call stack which is symbolized on demand
+//
+// The program counters are resolved into frames at the first call of frames,
+// and only up to the caller at the first call of caller.
+// Both results are cached and safe for concurrent use.
+type stack struct {
+	pcs    []uintptr
+	config *StackConfig
+	inner  *stack
+	once   sync.Once
+	own    []frame
+	frame  []frame
+
+	// callerFrame is the cached result of caller
+	callerFrame atomic.Pointer[frame]
+
+	// skipRuntime is the SkipRuntimeStackTrace when the stack was captured
+	skipRuntime bool
+}
+
+// newStack returns a stack of the program counters to be resolved with the config
+func newStack(pcs []uintptr, cfg *StackConfig) *stack {
+	return &stack{
+		pcs:         pcs,
+		config:      cfg,
+		skipRuntime: SkipRuntimeStackTrace,
+	}
+}
+
+// resolve symbolizes the program counters once
+//
+// The reported frames are the deeper one of its own frames and the frames of the inner stack,
+// the stack of a wrapped error.
+func (s *stack) resolve() {
+	s.once.Do(func() {
+		s.own = s.resolveFrames(-1)
+		s.frame = s.own
+
+		if inner := s.inner.frames(); len(inner) > len(s.own) {
+			s.frame = inner
+		}
+	})
+}
+
+// newResolvedStack returns a stack of already resolved frames
+func newResolvedStack(frames []frame) *stack {
+	s := &stack{
+		own:   frames,
+		frame: frames,
+	}
+	s.once.Do(func() {})
+
+	return s
+}
+
+// frames returns the resolved frames of the stack
+func (s *stack) frames() []frame {
+	if s == nil {
+		return nil
+	}
+
+	s.resolve()
+	return s.frame
+}
+
+// caller returns the first frame kept from its own program counters
+//
+// Only the frames up to the caller are symbolized, the stack is not resolved by it.
+func (s *stack) caller() frame {
+	if s == nil {
+		return frame{}
+	}
+
+	if s.pcs == nil {
+		if len(s.own) == 0 {
+			return frame{}
+		}
+
+		return s.own[0]
+	}
+
+	if f := s.callerFrame.Load(); f != nil {
+		return *f
+	}
+
+	var f frame
+	if own := s.resolveFrames(1); len(own) != 0 {
+		f = own[0]
+	}
+	s.callerFrame.Store(&f)
+
+	return f
+}
+
+// resolveFrames symbolizes the program counters into the frames kept by the config,
+// up to n frames, or up to its MaxDepth if n is negative
+func (s *stack) resolveFrames(n int) []frame {
+	if len(s.pcs) == 0 {
+		return nil
+	}
+
+	cfg := s.config
+	if cfg == nil {
+		cfg = loadStackConfig()
+	}
+
+	if n < 0 {
+		n = cfg.maxDepth()
+	}
+
+	var frames []frame
+
+	callersFrames := runtime.CallersFrames(s.pcs)
+
+	for len(frames) < n {
+		f, more := callersFrames.Next()
+
+		if f.Function != "" && cfg.keep(f, s.skipRuntime) {
+			funcName := f.Function
+			funcName = funcName[strings.LastIndexByte(funcName, '/')+1:]
+			funcName = funcName[strings.LastIndexByte(funcName, '.')+1:]
+
+			pkg := packagePath(f.Function)
+
+			frames = append(frames, frame{
+				File:         cfg.file(f.File, pkg),
+				Function:     funcName,
+				Line:         strconv.Itoa(f.Line),
+				Package:      pkg,
+				Receiver:     receiverName(f.Function, pkg),
+				FullFunction: f.Function,
+			})
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// receiverName returns the receiver type of a method, e.g. "*Service" of "pkg.(*Service).Handle"
+//
+// It returns an empty string if the function is not a method.
+func receiverName(function, pkg string) string {
+	name := strings.TrimPrefix(function, pkg+".")
+	if strings.HasPrefix(name, "(") {
+		if end := strings.Index(name, ")."); end > 0 {
+			return name[1:end]
+		}
+
+		return ""
+	}
+
+	receiver, method, found := strings.Cut(name, ".")
+	if !found || isClosureName(method) {
+		return ""
+	}
+
+	return receiver
+}
+
+// isClosureName reports whether the name is generated for a closure or a package variable initializer,
+// e.g. "func1" of "pkg.TestNew.func1" or "init.func1"
+func isClosureName(name string) bool {
+	name, _, _ = strings.Cut(name, ".")
+	if strings.HasPrefix(name, "func") {
+		_, err := strconv.Atoi(name[len("func"):])
+		return err == nil
+	}
+
+	return strings.HasPrefix(name, "gowrap") || strings.HasPrefix(name, "deferwrap")
+}
+
 // frame represents a single frame in the stack trace
 type frame struct {
-	File     string `json:"file"`
-	Function string `json:"function"`
-	Line     string `json:"line"`
+	File         string `json:"file"`
+	Function     string `json:"function"`
+	Line         string `json:"line"`
+	Package      string `json:"package,omitempty"`
+	Receiver     string `json:"receiver,omitempty"`
+	FullFunction string `json:"full_function,omitempty"`
+}
+
+// name returns the function name of the frame to render,
+// the full function name if full is set and the frame has it
+func (f frame) name(full bool) string {
+	if full && f.FullFunction != "" {
+		return f.FullFunction
+	}
+
+	return f.Function
 }
 
 func (f frame) FormatText() string {
@@ -19,12 +211,7 @@ func (f frame) FormatText() string {
 	buf.Reset()
 
 	buf.Grow(len(f.File) + len(f.Line) + len(f.Function) + 4)
-
-	buf.WriteString(f.File)
-	buf.WriteByte(':')
-	buf.WriteString(f.Line)
-	buf.WriteString(" in ")
-	buf.WriteString(f.Function)
+	f.writeText(buf, false)
 
 	return buf.String()
 }
@@ -35,9 +222,22 @@ func (f frame) FormatColorized(funcColor, fileColor string) string {
 	buf.Reset()
 
 	buf.Grow(len(f.File) + len(f.Line) + len(f.Function) + 4)
-
-	colorize.WriteString(buf, funcColor, "[", f.Function, "] ")
-	colorize.WriteString(buf, fileColor, f.File+":"+f.Line)
+	f.writeColorized(buf, funcColor, fileColor, false)
 
 	return buf.String()
 }
+
+// writeText writes the frame as "file:line in function"
+func (f frame) writeText(buf textWriter, full bool) {
+	buf.WriteString(f.File)
+	buf.WriteByte(':')
+	buf.WriteString(f.Line)
+	buf.WriteString(" in ")
+	buf.WriteString(f.name(full))
+}
+
+// writeColorized writes the frame as "[function] file:line" with the colors
+func (f frame) writeColorized(buf textWriter, funcColor, fileColor string, full bool) {
+	colorize.WriteString(buf, funcColor, "[", f.name(full), "] ")
+	colorize.WriteString(buf, fileColor, f.File, ":", f.Line)
+}
@@ -0,0 +1,212 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStackLazy(t *testing.T) {
+	err := New("test error").(*errorStack)
+
+	if err.stack.frame != nil {
+		t.Fatal("Expected stack to be unresolved after New")
+	}
+
+	if err.Error() != "test error" {
+		t.Fatalf("Expected 'test error', got '%s'", err.Error())
+	}
+
+	if err.stack.frame != nil {
+		t.Fatal("Expected stack to be unresolved after Error")
+	}
+
+	_ = Format(err)
+
+	if len(err.stack.frame) == 0 {
+		t.Fatal("Expected stack to be resolved after Format")
+	}
+
+	if err.stack.frames()[0].Function != "TestStackLazy" {
+		t.Errorf("Expected TestStackLazy, got %s", err.stack.frames()[0].Function)
+	}
+}
+
+func TestStackCaller(t *testing.T) {
+	err := New("test error").(*errorStack)
+	_ = err.With("k1", "v1").With("k2", 2)
+
+	if err.stack.frame != nil {
+		t.Fatal("Expected stack to be unresolved after With")
+	}
+
+	f := err.stack.callerFrame.Load()
+	if f == nil || f.Function != "TestStackCaller" {
+		t.Fatalf("Expected cached caller TestStackCaller, got %+v", f)
+	}
+
+	if c := err.stack.caller(); c != *f {
+		t.Errorf("Expected the cached caller, got %+v", c)
+	}
+}
+
+func TestStackConcurrent(t *testing.T) {
+	err := Wrap(New("test error"), "wrapped").(*errorStack)
+
+	var (
+		wg      sync.WaitGroup
+		results = make([][]frame, 8)
+	)
+
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = FormatJson(err)
+			results[i] = err.stack.frames()
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range results {
+		if len(results[i]) == 0 || &results[i][0] != &results[0][0] {
+			t.Fatalf("Expected every goroutine to share the cached frames")
+		}
+	}
+}
+
+type frameService struct{}
+
+func (*frameService) Handle() Error {
+	return New("pointer receiver")
+}
+
+func (frameService) Serve() Error {
+	return New("value receiver")
+}
+
+func TestFrameDetails(t *testing.T) {
+	f := (&frameService{}).Handle().(*errorStack).stack.caller()
+	if f.Function != "Handle" {
+		t.Errorf("Expected short name Handle, got %s", f.Function)
+	}
+
+	if f.Package != "github.com/yanun0323/errors" {
+		t.Errorf("Expected package github.com/yanun0323/errors, got %s", f.Package)
+	}
+
+	if f.Receiver != "*frameService" {
+		t.Errorf("Expected receiver *frameService, got %s", f.Receiver)
+	}
+
+	if f.FullFunction != "github.com/yanun0323/errors.(*frameService).Handle" {
+		t.Errorf("Expected full function name, got %s", f.FullFunction)
+	}
+
+	pkg, receiver, function := f.Details()
+	if pkg != f.Package || receiver != f.Receiver || function != f.FullFunction {
+		t.Errorf("Expected Details to return the frame details, got %s %s %s", pkg, receiver, function)
+	}
+}
+
+func TestReceiverName(t *testing.T) {
+	testCases := []struct {
+		function string
+		receiver string
+	}{
+		{"github.com/yanun0323/errors.(*frameService).Handle", "*frameService"},
+		{"github.com/yanun0323/errors.frameService.Serve", "frameService"},
+		{"github.com/yanun0323/errors.(*frameService).Handle.func1", "*frameService"},
+		{"github.com/yanun0323/errors.TestReceiverName.func1", ""},
+		{"github.com/yanun0323/errors.TestReceiverName.func1.2", ""},
+		{"github.com/yanun0323/errors.TestReceiverName", ""},
+		{"github.com/yanun0323/errors.init.func1", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := receiverName(tc.function, packagePath(tc.function)); got != tc.receiver {
+			t.Errorf("Expected receiver of '%s' to be '%s', got '%s'", tc.function, tc.receiver, got)
+		}
+	}
+}
+
+func TestFullFunctionNames(t *testing.T) {
+	err := frameService{}.Serve().With("k1", "v1")
+
+	const name = "github.com/yanun0323/errors.frameService.Serve"
+
+	if f := (TextFormatter{FullFunctionNames: true}).Format(err); !containsString(f, "    "+name+":\n") || !containsString(f, " in "+name+"\n") {
+		t.Errorf("Expected full function name in text format, got '%s'", f)
+	}
+
+	if f := Format(err); containsString(f, name) {
+		t.Errorf("Expected short function name in the default text format, got '%s'", f)
+	}
+
+	if f := (ColorizedFormatter{FullFunctionNames: true}).Format(err); !containsString(f, "["+name+"] ") {
+		t.Errorf("Expected full function name in colorized format, got '%s'", f)
+	}
+
+	if f := (LogfmtFormatter{FullFunctionNames: true}).Format(err); !containsString(f, name+".k1=v1") || !containsString(f, name+"@") {
+		t.Errorf("Expected full function name in logfmt format, got '%s'", f)
+	}
+
+	if f := FormatJson(err); !containsString(f, `"function": "Serve"`) || !containsString(f, `"full_function": "`+name+`"`) {
+		t.Errorf("Expected both function names in json format, got '%s'", f)
+	}
+}
+
+func TestStackOf(t *testing.T) {
+	err := frameService{}.Serve()
+
+	frames := StackOf(fmt.Errorf("handler: %w", err))
+	if len(frames) == 0 {
+		t.Fatal("Expected frames behind a foreign wrapper")
+	}
+
+	f := frames[0]
+	if f.Function != "Serve" || f.FullFunction != "github.com/yanun0323/errors.frameService.Serve" || f.Receiver != "frameService" {
+		t.Errorf("Expected the frame of frameService.Serve, got %+v", f)
+	}
+
+	if f.Line == 0 || !strings.HasSuffix(f.File, "frame_test.go") {
+		t.Errorf("Expected the file and line of frameService.Serve, got %+v", f)
+	}
+
+	if frames := StackOf(Join(fmt.Errorf("plain"), err)); len(frames) == 0 {
+		t.Error("Expected frames of a joined error")
+	}
+
+	if frames := StackOf(fmt.Errorf("plain")); frames != nil {
+		t.Errorf("Expected no frames for a plain error, got %+v", frames)
+	}
+
+	if frames := StackOf(nil); frames != nil {
+		t.Errorf("Expected no frames for nil, got %+v", frames)
+	}
+}
+
+func TestNewWithStack(t *testing.T) {
+	stack := []Frame{
+		{File: "/srv/user.go", Function: "findUser", Line: 42},
+		{File: "/srv/server.go", Function: "serve", Line: 7},
+	}
+
+	err := WithCode(NewWithStack("user not found", stack), "user.not_found").With("user_id", 123)
+	if err.Error() != "user not found" || CodeOf(err) != "user.not_found" {
+		t.Errorf("Expected message and code, got '%s' '%s'", err.Error(), CodeOf(err))
+	}
+
+	if frames := StackOf(err); len(frames) != 2 || frames[0] != stack[0] || frames[1] != stack[1] {
+		t.Errorf("Expected the given stack, got %+v", frames)
+	}
+
+	if fields := Fields(err); len(fields) != 1 || fields[0].Function != "findUser" {
+		t.Errorf("Expected the field attributed to the first frame, got %+v", fields)
+	}
+
+	if f := Format(err); !strings.Contains(f, "/srv/user.go:42 in findUser") {
+		t.Errorf("Expected the given stack in text format, got '%s'", f)
+	}
+}
@@ -0,0 +1,312 @@
+// Package grpcerr converts between errors.Error and gRPC *status.Status.
+//
+// The gRPC code of an error is resolved from the registered codes, categories
+// and sentinel errors, in that order. The code and the fields of the error are
+// carried by a google.rpc.ErrorInfo detail, and the stack trace is carried by
+// a google.rpc.DebugInfo detail when WithStack is used.
+//
+//	grpcerr.RegisterCategory("auth", codes.Unauthenticated)
+//	grpcerr.RegisterSentinel(ErrUserNotFound, codes.NotFound)
+//
+//	// server side
+//	return nil, grpcerr.ToStatus(err).Err()
+//
+//	// client side
+//	err := grpcerr.FromError(err)
+//	errors.CodeOf(err) // the code of the error returned by the server
+package grpcerr
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yanun0323/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// DefaultDomain is the domain of the ErrorInfo detail if no domain is given
+const DefaultDomain = "github.com/yanun0323/errors"
+
+var registry = struct {
+	sync.RWMutex
+	code     map[errors.Code]codes.Code
+	category map[string]codes.Code
+	sentinel []sentinel
+}{
+	code:     map[errors.Code]codes.Code{},
+	category: map[string]codes.Code{},
+}
+
+type sentinel struct {
+	err  error
+	code codes.Code
+}
+
+// Register maps the error code to the gRPC code
+func Register(code errors.Code, c codes.Code) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.code[code] = c
+}
+
+// RegisterCategory maps every error code in the category to the gRPC code
+func RegisterCategory(category string, c codes.Code) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.category[category] = c
+}
+
+// RegisterSentinel maps the errors matching the sentinel error (by errors.Is) to the gRPC code
+func RegisterSentinel(err error, c codes.Code) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.sentinel = append(registry.sentinel, sentinel{err: err, code: c})
+}
+
+// Option configures the conversion of ToStatus
+type Option func(*options)
+
+type options struct {
+	domain string
+	stack  bool
+}
+
+// WithDomain sets the domain of the ErrorInfo detail
+func WithDomain(domain string) Option {
+	return func(o *options) {
+		o.domain = domain
+	}
+}
+
+// WithStack adds the stack trace of the error as a DebugInfo detail
+func WithStack() Option {
+	return func(o *options) {
+		o.stack = true
+	}
+}
+
+// CodeOf returns the gRPC code of the error
+//
+// It returns codes.OK for a nil error, and codes.Unknown if no registered
+// code, category or sentinel error matches.
+func CodeOf(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+
+	if s, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return s.GRPCStatus().Code()
+	}
+
+	registry.RLock()
+	defer registry.RUnlock()
+
+	if code := errors.CodeOf(err); code != "" {
+		if c, ok := registry.code[code]; ok {
+			return c
+		}
+
+		if c, ok := registry.category[code.Category()]; ok {
+			return c
+		}
+	}
+
+	for _, s := range registry.sentinel {
+		if errors.Is(err, s.err) {
+			return s.code
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	}
+
+	return codes.Unknown
+}
+
+// ToStatus converts the error to a gRPC status
+//
+// The message of the status is the message of the error, the code and the
+// fields of the error are added as an ErrorInfo detail. It returns nil for a
+// nil error, and the status of the error if it is already a gRPC status error.
+func ToStatus(err error, opts ...Option) *status.Status {
+	if err == nil {
+		return nil
+	}
+
+	if s, ok := err.(interface{ GRPCStatus() *status.Status }); ok {
+		return s.GRPCStatus()
+	}
+
+	o := options{domain: DefaultDomain}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	s := status.New(CodeOf(err), err.Error())
+
+	info := &errdetails.ErrorInfo{
+		Reason: string(errors.CodeOf(err)),
+		Domain: o.domain,
+	}
+
+	if fields := errors.Fields(err); len(fields) != 0 {
+		info.Metadata = make(map[string]string, len(fields))
+		for _, f := range fields {
+			info.Metadata[f.Key] = fmt.Sprintf("%+v", f.Value)
+		}
+	}
+
+	details := []protoadapt.MessageV1{info}
+	if stack := errors.StackOf(err); o.stack && len(stack) != 0 {
+		debug := &errdetails.DebugInfo{
+			StackEntries: make([]string, 0, len(stack)),
+			Detail:       err.Error(),
+		}
+
+		for _, f := range stack {
+			debug.StackEntries = append(debug.StackEntries, stackEntry(f))
+		}
+
+		details = append(details, debug)
+	}
+
+	withDetails, e := s.WithDetails(details...)
+	if e != nil {
+		return s
+	}
+
+	return withDetails
+}
+
+// FromStatus converts the gRPC status to an Error
+//
+// The code and the fields of the error are restored from the ErrorInfo detail,
+// the stack trace is restored from the DebugInfo detail. It returns nil for a
+// nil or OK status.
+func FromStatus(s *status.Status) errors.Error {
+	if s == nil || s.Code() == codes.OK {
+		return nil
+	}
+
+	var (
+		code  errors.Code
+		info  map[string]string
+		stack []errors.Frame
+	)
+
+	for _, d := range s.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			code = errors.Code(d.Reason)
+			info = d.Metadata
+		case *errdetails.DebugInfo:
+			for _, entry := range d.StackEntries {
+				stack = append(stack, parseFrame(entry))
+			}
+		}
+	}
+
+	err := errors.NewWithStack(s.Message(), stack)
+	if code != "" {
+		err = errors.WithCode(err, code)
+	}
+
+	if len(info) != 0 {
+		keys := make([]string, 0, len(info))
+		for k := range info {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		fields := make([]any, 0, 2*len(keys))
+		for _, k := range keys {
+			fields = append(fields, k, info[k])
+		}
+
+		err = err.With(fields...)
+	}
+
+	return err
+}
+
+// FromError converts the error returned by a gRPC call to an Error
+//
+// It returns nil for a nil error, and wraps the error if it is not a gRPC status error.
+func FromError(err error) errors.Error {
+	if err == nil {
+		return nil
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return errors.Wrap(err)
+	}
+
+	return FromStatus(s)
+}
+
+// UnaryServerInterceptor returns a server interceptor which converts the returned errors to gRPC status errors
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, ToStatus(err, opts...).Err()
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor returns a stream server interceptor which converts the returned errors to gRPC status errors
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return ToStatus(err, opts...).Err()
+		}
+
+		return nil
+	}
+}
+
+/*
+	########  ########  #### ##     ##    ###    ######## ########
+	##     ## ##     ##  ##  ##     ##   ## ##      ##    ##
+	##     ## ##     ##  ##  ##     ##  ##   ##     ##    ##
+	########  ########   ##  ##     ## ##     ##    ##    ######
+	##        ##   ##    ##   ##   ##  #########    ##    ##
+	##        ##    ##   ##    ## ##   ##     ##    ##    ##
+	##        ##     ## ####    ###    ##     ##    ##    ########
+*/
+
+// stackEntry returns the frame as a DebugInfo stack entry
+func stackEntry(f errors.Frame) string {
+	return f.File + ":" + strconv.Itoa(f.Line) + " in " + f.Function
+}
+
+// parseFrame parses a DebugInfo stack entry
+func parseFrame(entry string) errors.Frame {
+	location, function, _ := strings.Cut(entry, " in ")
+	f := errors.Frame{File: location, Function: function}
+	if i := strings.LastIndexByte(location, ':'); i >= 0 {
+		if line, e := strconv.Atoi(location[i+1:]); e == nil {
+			f.File, f.Line = location[:i], line
+		}
+	}
+
+	return f
+}
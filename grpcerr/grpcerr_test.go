@@ -0,0 +1,181 @@
+package grpcerr
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/yanun0323/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var errUserNotFound = errors.New("user not found")
+
+func init() {
+	Register("auth.forbidden", codes.PermissionDenied)
+	RegisterCategory("auth", codes.Unauthenticated)
+	RegisterSentinel(errUserNotFound, codes.NotFound)
+}
+
+func TestCodeOf(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected codes.Code
+	}{
+		{"nil", nil, codes.OK},
+		{"code", errors.WithCode(errors.New("forbidden"), "auth.forbidden"), codes.PermissionDenied},
+		{"category", errors.WithCode(errors.New("expired"), "auth.token_expired"), codes.Unauthenticated},
+		{"wrapped category", errors.Wrap(errors.WithCode(errors.New("expired"), "auth.token_expired"), "login"), codes.Unauthenticated},
+		{"sentinel", errors.Wrap(errUserNotFound, "get user"), codes.NotFound},
+		{"deadline", errors.Wrap(context.DeadlineExceeded), codes.DeadlineExceeded},
+		{"status", status.Error(codes.Aborted, "aborted"), codes.Aborted},
+		{"unknown", errors.New("unknown"), codes.Unknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if c := CodeOf(tc.err); c != tc.expected {
+				t.Errorf("Expected code %s, got %s", tc.expected, c)
+			}
+		})
+	}
+}
+
+func TestToStatus(t *testing.T) {
+	if s := ToStatus(nil); s != nil {
+		t.Errorf("Expected nil status, got %v", s)
+	}
+
+	err := errors.WithCode(errors.New("expired"), "auth.token_expired").With("user_id", 123, "retry", true)
+
+	s := ToStatus(err, WithDomain("test"), WithStack())
+	if s.Code() != codes.Unauthenticated || s.Message() != "expired" {
+		t.Errorf("Expected status Unauthenticated 'expired', got %s '%s'", s.Code(), s.Message())
+	}
+
+	e := FromStatus(s)
+	if e.Error() != "expired" {
+		t.Errorf("Expected message 'expired', got '%s'", e.Error())
+	}
+
+	if code := errors.CodeOf(e); code != "auth.token_expired" {
+		t.Errorf("Expected code 'auth.token_expired', got '%s'", code)
+	}
+
+	text := errors.Format(e)
+	for _, expected := range []string{"retry: true", "user_id: 123", "grpcerr_test.go:", "in TestToStatus"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected text format to contain '%s', got '%s'", expected, text)
+		}
+	}
+
+	if FromStatus(status.New(codes.OK, "")) != nil {
+		t.Error("Expected nil error of OK status")
+	}
+}
+
+func TestParseFrame(t *testing.T) {
+	f := parseFrame("/path/to/file.go:42 in handler")
+	if f != (errors.Frame{File: "/path/to/file.go", Function: "handler", Line: 42}) {
+		t.Errorf("Expected parsed frame, got %+v", f)
+	}
+
+	if entry := stackEntry(f); entry != "/path/to/file.go:42 in handler" {
+		t.Errorf("Expected the same stack entry, got '%s'", entry)
+	}
+}
+
+// dial starts an in-process server which handles every method with the handler
+func dial(t *testing.T, handler func(name string) error) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(
+		grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+			req := &wrapperspb.StringValue{}
+			if err := stream.RecvMsg(req); err != nil {
+				return err
+			}
+
+			if err := handler(req.GetValue()); err != nil {
+				return err
+			}
+
+			return stream.SendMsg(req)
+		}),
+		grpc.StreamInterceptor(StreamServerInterceptor(WithStack())),
+	)
+
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func findUser(name string) error {
+	if name == "" {
+		return errors.WithCode(errors.New("empty name"), "user.invalid_name")
+	}
+
+	return errors.Wrap(errUserNotFound, "find user").With("name", name)
+}
+
+func TestBufconn(t *testing.T) {
+	conn := dial(t, findUser)
+
+	testCases := []struct {
+		name    string
+		code    codes.Code
+		errCode errors.Code
+		field   string
+	}{
+		{"yanun", codes.NotFound, "", "name: yanun"},
+		{"", codes.Unknown, "user.invalid_name", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := wrapperspb.String(tc.name)
+			err := conn.Invoke(context.Background(), "/user.UserService/FindUser", req, &wrapperspb.StringValue{})
+			if status.Code(err) != tc.code {
+				t.Fatalf("Expected code %s, got %s", tc.code, status.Code(err))
+			}
+
+			e := FromError(err)
+			if code := errors.CodeOf(e); code != tc.errCode {
+				t.Errorf("Expected code '%s', got '%s'", tc.errCode, code)
+			}
+
+			text := errors.Format(e)
+			if tc.field != "" && !strings.Contains(text, tc.field) {
+				t.Errorf("Expected text format to contain '%s', got '%s'", tc.field, text)
+			}
+
+			if !strings.Contains(text, "in findUser") {
+				t.Errorf("Expected the stack of the server, got '%s'", text)
+			}
+		})
+	}
+
+	if FromError(nil) != nil {
+		t.Error("Expected nil error")
+	}
+}
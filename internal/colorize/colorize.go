@@ -81,8 +81,15 @@ type Writer interface {
 	io.ByteWriter
 }
 
-// WriteString write colorized string to buffer
+// WriteString write colorized string to buffer, the contents are written without color codes if color is empty
 func WriteString(buf Writer, color string, contents ...string) {
+	if color == "" {
+		for _, s := range contents {
+			buf.WriteString(s)
+		}
+		return
+	}
+
 	buf.WriteString(color)
 	for _, s := range contents {
 		buf.WriteString(s)
@@ -90,8 +97,15 @@ func WriteString(buf Writer, color string, contents ...string) {
 	buf.WriteString(Reset)
 }
 
-// WriteBytes write colorized bytes to buffer
+// WriteBytes write colorized bytes to buffer, the contents are written without color codes if color is empty
 func WriteBytes(buf Writer, color string, contents ...[]byte) {
+	if color == "" {
+		for _, b := range contents {
+			buf.Write(b)
+		}
+		return
+	}
+
 	buf.WriteString(color)
 	for _, b := range contents {
 		buf.Write(b)
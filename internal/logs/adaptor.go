@@ -11,6 +11,7 @@ type Error interface {
 // Frame is the interface for supporting logs (github.com/yanun0323/logs)
 type Frame interface {
 	Parameters() (file, function, line string)
+	Details() (pkg, receiver, function string)
 }
 
 // Attr is the interface for supporting logs (github.com/yanun0323/logs)
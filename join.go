@@ -0,0 +1,299 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/yanun0323/errors/internal/colorize"
+)
+
+// make joinError implements Error and formatting interfaces
+var (
+	_ Error          = (*joinError)(nil)
+	_ fmt.Formatter  = (*joinError)(nil)
+	_ json.Marshaler = (*joinError)(nil)
+	_ slog.LogValuer = (*joinError)(nil)
+	_ formattable    = (*joinError)(nil)
+	_ formattable    = (*errorStack)(nil)
+)
+
+// formattable is implemented by the errors which can be rendered in every format
+type formattable interface {
+	writeText(buf textWriter, full bool)
+	writeColorized(buf textWriter, t *Theme, full bool)
+	toJSON() *errorJSON
+}
+
+/*
+	########  ##     ## ########  ##       ####  ######
+	##     ## ##     ## ##     ## ##        ##  ##    ##
+	##     ## ##     ## ##     ## ##        ##  ##
+	########  ##     ## ########  ##        ##  ##
+	##        ##     ## ##     ## ##        ##  ##
+	##        ##     ## ##     ## ##        ##  ##    ##
+	##         #######  ########  ######## ####  ######
+*/
+
+// Format implements the fmt.Formatter interface
+//
+// '%s' - error message
+// '%v' - text format
+// '%+v' - colorized format
+// '%#v' - json format
+//
+// The formats of the '%v' verbs can be replaced by SetVerb.
+func (e *joinError) Format(f fmt.State, c rune) {
+	if e == nil {
+		return
+	}
+
+	formatVerb(e, f, c)
+}
+
+// With adds additional fields, supporting method chaining
+func (e *joinError) With(args ...any) Error {
+	if e == nil {
+		return nil
+	}
+
+	c := *e
+	c.attr = make([]attr, 0, len(e.attr)+len(args)/2)
+	c.attr = append(c.attr, e.attr...)
+	c.attr = append(c.attr, redactAttrs(e.redaction, makeArgs(e.lastCaller(), args...))...)
+
+	return &c
+}
+
+func (e *joinError) WithMap(m map[string]any) Error {
+	if e == nil {
+		return nil
+	}
+
+	c := *e
+	c.attr = make([]attr, 0, len(e.attr)+len(m))
+	c.attr = append(c.attr, e.attr...)
+	caller := e.lastCaller()
+	for k, v := range m {
+		c.attr = append(c.attr, newAttr(caller, k, v))
+	}
+	redactAttrs(e.redaction, c.attr[len(e.attr):])
+
+	return &c
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (e *joinError) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(e.toJSON())
+}
+
+// LogValue implements the slog.LogValuer interface
+func (e *joinError) LogValue() slog.Value {
+	if e == nil {
+		return slog.Value{}
+	}
+
+	attrs := make([]slog.Attr, 0, 4)
+	attrs = append(attrs, slog.String("message", e.Error()))
+
+	if e.code != "" {
+		attrs = append(attrs, slog.String("code", string(e.code)))
+	}
+
+	if len(e.attr) != 0 {
+		fields := make([]slog.Attr, 0, len(e.attr))
+		for _, a := range e.attr {
+			fields = append(fields, slog.Any(a.Key, a.Value))
+		}
+
+		attrs = append(attrs, slog.Attr{Key: "field", Value: slog.GroupValue(fields...)})
+	}
+
+	if stack := e.stack.frames(); len(stack) != 0 {
+		frames := make([]string, 0, len(stack))
+		for _, f := range stack {
+			frames = append(frames, f.FormatText())
+		}
+
+		attrs = append(attrs, slog.Any("stack", frames))
+	}
+
+	branches := make([]slog.Attr, 0, len(e.errs))
+	for i, err := range e.errs {
+		branches = append(branches, slog.Any(strconv.Itoa(i), err))
+	}
+
+	attrs = append(attrs, slog.Attr{Key: "join", Value: slog.GroupValue(branches...)})
+
+	return slog.GroupValue(attrs...)
+}
+
+// String returns basic string format
+func (e *joinError) String() string {
+	return e.Error()
+}
+
+/*
+	########  ########  #### ##     ##    ###    ######## ########
+	##     ## ##     ##  ##  ##     ##   ## ##      ##    ##
+	##     ## ##     ##  ##  ##     ##  ##   ##     ##    ##
+	########  ########   ##  ##     ## ##     ##    ##    ######
+	##        ##   ##    ##   ##   ##  #########    ##    ##
+	##        ##    ##   ##    ## ##   ##     ##    ##    ##
+	##        ##     ## ####    ###    ##     ##    ##    ########
+*/
+
+// lastCaller returns the frame where the errors were joined
+func (e *joinError) lastCaller() frame {
+	return e.stack.caller()
+}
+
+// writeText writes text formatted error information,
+// every joined error is rendered as an indexed branch
+func (e *joinError) writeText(buf textWriter, full bool) {
+	if e == nil {
+		return
+	}
+
+	buf.WriteString("error:\n")
+	writeIndented(buf, _tab, e.Error())
+
+	if e.code != "" {
+		buf.WriteString("code:\n")
+		buf.WriteString(_tab)
+		buf.WriteString(string(e.code))
+		buf.WriteByte('\n')
+	}
+
+	if len(e.attr) != 0 {
+		buf.WriteString("field:\n")
+		for _, a := range e.attr {
+			buf.WriteString(_tab)
+			buf.WriteString(a.Key)
+			buf.WriteString(": ")
+			fmt.Fprintf(buf, "%+v", a.Value)
+			buf.WriteByte('\n')
+		}
+	}
+
+	writeStackText(buf, e.stack.frames(), full)
+
+	buf.WriteString("join:\n")
+	for i, err := range e.errs {
+		buf.WriteString(_tab)
+		buf.WriteByte('[')
+		buf.WriteString(strconv.Itoa(i))
+		buf.WriteByte(']')
+		if path := e.path(i); path != "" {
+			buf.WriteByte(' ')
+			buf.WriteString(path)
+		}
+		buf.WriteString(":\n")
+
+		if f, ok := err.(formattable); ok {
+			f.writeText(newIndentWriter(buf, _tab+_tab), full)
+			continue
+		}
+
+		buf.WriteString(_tab)
+		buf.WriteString(_tab)
+		buf.WriteString("error:\n")
+		writeIndented(buf, _tab+_tab+_tab, err.Error())
+	}
+}
+
+// writeColorized writes colorized readable format (ANSI color codes),
+// every joined error is rendered as an indexed branch
+func (e *joinError) writeColorized(buf textWriter, t *Theme, full bool) {
+	if e == nil {
+		return
+	}
+
+	colorize.WriteString(buf, t.Error, "[error] ")
+	for i, line := range strings.Split(e.Error(), "\n") {
+		if i != 0 {
+			buf.WriteString(_errorPadding)
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+
+	if e.code != "" {
+		colorize.WriteString(buf, t.Code, "[code] ")
+		buf.WriteString(string(e.code))
+		buf.WriteByte('\n')
+	}
+
+	if len(e.attr) > 0 {
+		colorize.WriteString(buf, t.Section, "[field]")
+		buf.WriteByte('\n')
+		for _, a := range e.attr {
+			buf.WriteString(_tab)
+			colorize.WriteString(buf, t.Key, "[", a.Key, "] ")
+			writeValueColorized(buf, t.Value, a.Value)
+		}
+	}
+
+	writeStackColorized(buf, t, e.stack.frames(), full)
+
+	colorize.WriteString(buf, t.Section, "[join]")
+	buf.WriteByte('\n')
+	for i, err := range e.errs {
+		buf.WriteString(_tab)
+		colorize.WriteString(buf, t.Index, "[", strconv.Itoa(i), "]")
+		if path := e.path(i); path != "" {
+			buf.WriteByte(' ')
+			colorize.WriteString(buf, t.Key, path)
+		}
+		buf.WriteByte('\n')
+
+		if f, ok := err.(formattable); ok {
+			f.writeColorized(newIndentWriter(buf, _tab+_tab), t, full)
+			continue
+		}
+
+		buf.WriteString(_tab)
+		buf.WriteString(_tab)
+		colorize.WriteString(buf, t.Error, "[error] ")
+		buf.WriteString(err.Error())
+		buf.WriteByte('\n')
+	}
+}
+
+func (e *joinError) toJSON() *errorJSON {
+	v := &errorJSON{
+		Error: e.Error(),
+		Code:  e.code,
+		Field: e.attr,
+		Stack: e.stack.frames(),
+		Join:  make([]*errorJSON, 0, len(e.errs)),
+	}
+	v.classify(e)
+
+	for i, err := range e.errs {
+		branch := causeToJSON(err)
+		if path := e.path(i); path != "" {
+			branch.Path = path
+
+			if v.Paths == nil {
+				v.Paths = make(map[string]string, len(e.errs))
+			}
+
+			if msg, ok := v.Paths[path]; ok {
+				v.Paths[path] = msg + "; " + err.Error()
+			} else {
+				v.Paths[path] = err.Error()
+			}
+		}
+
+		v.Join = append(v.Join, branch)
+	}
+
+	return v
+}
@@ -0,0 +1,215 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// make errorStack implements json.Marshaler
+var _ json.Marshaler = (*errorStack)(nil)
+
+// errorJSON is the JSON representation of an error
+//
+// The fields are encoded in the order of the struct fields,
+// the cause is encoded as a nested errorJSON instead of its message,
+// the chain lists the wrap layers when the error wraps another error,
+// the join lists the joined errors of an error returned by Join,
+// the class and the retry_after are the classification of the whole error chain,
+// the paths map the paths of the errors collected by a Collector to their messages.
+type errorJSON struct {
+	Error string            `json:"error"`
+	Path  string            `json:"path,omitempty"`
+	Code  Code              `json:"code,omitempty"`
+	Class Class             `json:"class,omitempty"`
+	Retry string            `json:"retry_after,omitempty"`
+	Cause *errorJSON        `json:"cause,omitempty"`
+	Chain []layerJSON       `json:"chain,omitempty"`
+	Field []attr            `json:"field,omitempty"`
+	Stack []frame           `json:"stack,omitempty"`
+	Paths map[string]string `json:"paths,omitempty"`
+	Join  []*errorJSON      `json:"join,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+//
+// The output is compact, use FormatJson for indented output.
+func (e *errorStack) MarshalJSON() ([]byte, error) {
+	if e == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(e.toJSON())
+}
+
+// FromJSON rebuilds an Error from the JSON produced by FormatJson or json.Marshal
+//
+// The rebuilt error keeps the message, code, fields, stack and cause chain,
+// so its code can be read by CodeOf. It is not the original error though:
+//   - the field values are decoded as JSON values, e.g. numbers are float64
+//     and structs are map[string]any
+//   - Is matches it by message, or by code for errors created by Define,
+//     the identity of the errors created by Sentinel is lost
+func FromJSON(data []byte) (Error, error) {
+	var v errorJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	if len(v.Join) != 0 {
+		return v.toJoinError(), nil
+	}
+
+	return v.toError(), nil
+}
+
+func (e *errorStack) toJSON() *errorJSON {
+	v := &errorJSON{
+		Error: e.message,
+		Code:  e.code,
+		Cause: causeToJSON(e.cause),
+		Field: e.attr,
+		Stack: e.stack.frames(),
+	}
+	v.classify(e)
+
+	if layers := e.chain(); len(layers) > 1 {
+		v.Chain = make([]layerJSON, 0, len(layers))
+		for _, l := range layers {
+			v.Chain = append(v.Chain, l.toJSON())
+		}
+	}
+
+	return v
+}
+
+// causeToJSON converts the cause chain of an error into errorJSON
+func causeToJSON(err error) *errorJSON {
+	if err == nil {
+		return nil
+	}
+
+	switch e := err.(type) {
+	case *errorStack:
+		if e == nil {
+			return nil
+		}
+		return e.toJSON()
+	case *joinError:
+		if e == nil {
+			return nil
+		}
+		return e.toJSON()
+	}
+
+	v := &errorJSON{
+		Error: err.Error(),
+	}
+
+	if u, ok := err.(unwrap); ok {
+		v.Cause = causeToJSON(u.Unwrap())
+	}
+
+	return v
+}
+
+// toError rebuilds the errorStack from its JSON representation
+func (v *errorJSON) toError() *errorStack {
+	e := &errorStack{
+		message: v.Error,
+		code:    v.Code,
+		cause:   v.Cause.toCause(),
+		stack:   newResolvedStack(v.Stack),
+		attr:    v.Field,
+		class:   v.Class,
+	}
+	e.retryAfter, _ = time.ParseDuration(v.Retry)
+
+	if len(v.Chain) > 1 {
+		e.wrapped = chainFromJSON(v.Chain[1:])
+
+		// the caller of a wrapping error is the frame where it was wrapped,
+		// not the first frame of the stack inherited from the wrapped error
+		e.stack.own = nil
+		if v.Chain[0].Caller != nil {
+			e.stack.own = []frame{*v.Chain[0].Caller}
+		}
+	}
+
+	return e
+}
+
+// toCause rebuilds a cause, a cause carrying only a message is rebuilt as errorString
+// to keep the message equality of Is
+func (v *errorJSON) toCause() error {
+	if v == nil {
+		return nil
+	}
+
+	if len(v.Join) != 0 {
+		return v.toJoinError()
+	}
+
+	if v.Code == "" && v.Class == 0 && v.Cause == nil && len(v.Field) == 0 && len(v.Stack) == 0 {
+		return errorString{message: v.Error}
+	}
+
+	return v.toError()
+}
+
+// toJoinError rebuilds the joinError from its JSON representation
+func (v *errorJSON) toJoinError() *joinError {
+	e := &joinError{
+		message: v.Error,
+		errs:    make([]error, 0, len(v.Join)),
+		code:    v.Code,
+		stack:   newResolvedStack(v.Stack),
+		attr:    v.Field,
+		class:   v.Class,
+	}
+	e.retryAfter, _ = time.ParseDuration(v.Retry)
+
+	for _, branch := range v.Join {
+		if err := branch.toCause(); err != nil {
+			e.errs = append(e.errs, err)
+			if len(v.Paths) != 0 {
+				e.paths = append(e.paths, branch.Path)
+			}
+		}
+	}
+
+	return e
+}
+
+// classify sets the classification of the error chain of err
+func (v *errorJSON) classify(err error) {
+	class, retryAfter := classify(err)
+
+	v.Class = class
+	if retryAfter > 0 {
+		v.Retry = retryAfter.String()
+	}
+}
+
+// writeJSON writes indented JSON of the error
+//
+// The JSON is encoded into a pooled buffer by a new encoder, a json.Encoder keeps
+// the first error of its writer, so it is not reused.
+func writeJSON(w io.Writer, v any) error {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	defer bufferPool.Put(buf)
+	buf.Reset()
+
+	enc := json.NewEncoder(buf)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(v); err != nil {
+		_, err = fmt.Fprintf(w, `{"error": "marshal error: %s"}`, err.Error())
+		return err
+	}
+
+	_, err := w.Write(bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}))
+	return err
+}
@@ -0,0 +1,81 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var errJSONSentinel = New("json sentinel")
+
+func TestMarshalJSON(t *testing.T) {
+	err := WithCode(Wrap(errJSONSentinel, "wrapped"), "json.failed").With("k1", "v1")
+
+	data, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("marshal error: %v", e)
+	}
+
+	s := string(data)
+	if strings.Contains(s, "\n") {
+		t.Errorf("Expected compact JSON, got '%s'", s)
+	}
+
+	prefix := `{"error":"wrapped, err: json sentinel","code":"json.failed","cause":{"error":"json sentinel"},"chain":[{"error":"wrapped, err: json sentinel","code":"json.failed","caller":{"file":`
+	if !strings.HasPrefix(s, prefix) {
+		t.Errorf("Expected JSON to start with '%s', got '%s'", prefix, s)
+	}
+
+	rest := `{"error":"json sentinel"}],"field":[{"function":"TestMarshalJSON","key":"k1","value":"v1"}],"stack":[{"file":`
+	if !strings.Contains(s, rest) {
+		t.Errorf("Expected JSON to contain '%s', got '%s'", rest, s)
+	}
+}
+
+func TestMarshalJSONForeignCause(t *testing.T) {
+	err := Wrap(&foreignError{message: "outer", err: &OtherError{msg: "inner"}})
+
+	data, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("marshal error: %v", e)
+	}
+
+	expected := `"cause":{"error":"outer","cause":{"error":"inner"}}`
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("Expected JSON to contain '%s', got '%s'", expected, data)
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	origin := WithCode(Wrap(errJSONSentinel, "wrapped"), "json.failed").With("k1", "v1", "k2", 2)
+
+	err, e := FromJSON([]byte(FormatJson(origin)))
+	if e != nil {
+		t.Fatalf("FromJSON error: %v", e)
+	}
+
+	if err.Error() != origin.Error() {
+		t.Errorf("Expected message '%s', got '%s'", origin.Error(), err.Error())
+	}
+
+	if !Is(err, errJSONSentinel) {
+		t.Error("Expected rebuilt error to match the sentinel error")
+	}
+
+	if code := CodeOf(err); code != "json.failed" {
+		t.Errorf("Expected code 'json.failed', got '%s'", code)
+	}
+
+	attrs := err.(*errorStack).attr
+	if len(attrs) != 2 || attrs[0].field() != (Field{"TestFromJSON", "k1", "v1"}) || attrs[1].field() != (Field{"TestFromJSON", "k2", float64(2)}) {
+		t.Errorf("Expected fields to be rebuilt, got %v", attrs)
+	}
+
+	if Format(err) != Format(origin) {
+		t.Errorf("Expected the same text format, got '%s', expected '%s'", Format(err), Format(origin))
+	}
+
+	if _, e := FromJSON([]byte("not json")); e == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
@@ -0,0 +1,166 @@
+package errors
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// LogfmtFormat is the name of the built-in logfmt formatter
+	LogfmtFormat = "logfmt"
+
+	_defaultLogfmtFrames = 5
+)
+
+// make LogfmtFormatter implements Formatter
+var _ Formatter = LogfmtFormatter{}
+
+// LogfmtFormatter renders an error as a single logfmt line, for line-oriented log pipelines
+//
+// The line has the message, the code, the cause, the fields keyed by the function
+// which attached them, and a compact stack, e.g.
+//
+//	error="handler, err: user not found" code=user.not_found cause="user not found" getUser.user_id=123 stack="getUser@user.go:12 handle@handler.go:30"
+//
+// The joined errors of Join are rendered as join.0, join.1 and so on.
+type LogfmtFormatter struct {
+	// MaxFrames is the maximum number of frames in the stack, 5 is used if it is zero,
+	// and the stack is omitted if it is negative.
+	MaxFrames int
+
+	// FullFunctionNames renders the stack and the field keys with the full function name,
+	// e.g. "github.com/acme/app.(*Service).Handle" instead of "Handle".
+	FullFunctionNames bool
+}
+
+// FormatLogfmt formats the error as a single logfmt line, see LogfmtFormatter
+func FormatLogfmt(err error) string {
+	return LogfmtFormatter{}.Format(err)
+}
+
+// Format implements the Formatter interface
+func (l LogfmtFormatter) Format(err error) string {
+	if err == nil {
+		return _emptyString
+	}
+
+	buf := stringBuilderPool.Get().(*strings.Builder)
+	defer stringBuilderPool.Put(buf)
+	buf.Reset()
+
+	writeLogfmt(buf, "error", err.Error())
+
+	switch e := err.(type) {
+	case *errorStack:
+		if e.code != "" {
+			writeLogfmt(buf, "code", string(e.code))
+		}
+
+		if e.cause != nil {
+			writeLogfmt(buf, "cause", e.cause.Error())
+		}
+
+		writeLogfmtFields(buf, e.attr, l.FullFunctionNames)
+		l.writeStack(buf, e.stack.frames())
+	case *joinError:
+		if e.code != "" {
+			writeLogfmt(buf, "code", string(e.code))
+		}
+
+		writeLogfmtFields(buf, e.attr, l.FullFunctionNames)
+		l.writeStack(buf, e.stack.frames())
+
+		for i := range e.errs {
+			writeLogfmt(buf, "join."+strconv.Itoa(i), e.branchMessage(i))
+		}
+	}
+
+	return buf.String()
+}
+
+/*
+	########  ########  #### ##     ##    ###    ######## ########
+	##     ## ##     ##  ##  ##     ##   ## ##      ##    ##
+	##     ## ##     ##  ##  ##     ##  ##   ##     ##    ##
+	########  ########   ##  ##     ## ##     ##    ##    ######
+	##        ##   ##    ##   ##   ##  #########    ##    ##
+	##        ##    ##   ##    ## ##   ##     ##    ##    ##
+	##        ##     ## ####    ###    ##     ##    ##    ########
+*/
+
+// writeStack writes the frames as "function@file:line" separated by spaces
+func (l LogfmtFormatter) writeStack(buf *strings.Builder, frames []frame) {
+	n := l.MaxFrames
+	if n == 0 {
+		n = _defaultLogfmtFrames
+	}
+
+	if n < 0 || len(frames) == 0 {
+		return
+	}
+
+	if len(frames) > n {
+		frames = frames[:n]
+	}
+
+	stack := make([]string, 0, len(frames))
+	for _, f := range frames {
+		stack = append(stack, f.name(l.FullFunctionNames)+"@"+path.Base(f.File)+":"+f.Line)
+	}
+
+	writeLogfmt(buf, "stack", strings.Join(stack, " "))
+}
+
+// writeLogfmtFields writes the fields keyed by the function which attached them
+func writeLogfmtFields(buf *strings.Builder, attrs []attr, full bool) {
+	for _, a := range attrs {
+		key := a.Key
+		if name := a.name(full); name != "" {
+			key = name + "." + key
+		}
+
+		writeLogfmt(buf, key, fmt.Sprintf("%+v", a.Value))
+	}
+}
+
+// writeLogfmt writes the key and the value as a logfmt pair,
+// the value is quoted if it is empty or has spaces, quotes, '=' or control characters
+func writeLogfmt(buf *strings.Builder, key string, value string) {
+	if buf.Len() != 0 {
+		buf.WriteByte(' ')
+	}
+
+	buf.WriteString(strings.Map(func(r rune) rune {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, key))
+	buf.WriteByte('=')
+
+	if needsQuote(value) {
+		buf.WriteString(strconv.Quote(value))
+		return
+	}
+
+	buf.WriteString(value)
+}
+
+// needsQuote reports whether the logfmt value must be quoted
+func needsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+
+	for _, r := range s {
+		if r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func findUser() Error {
+	return WithCode(New("user \"yanun\" not found"), "user.not_found").With("user_id", 123, "query", "name = yanun", "password", Secret("s3cr3t"))
+}
+
+func TestFormatLogfmt(t *testing.T) {
+	err := Wrap(findUser(), "handler").With("empty", "")
+
+	text := FormatLogfmt(err)
+	if strings.Contains(text, "\n") {
+		t.Fatalf("Expected a single line, got '%s'", text)
+	}
+
+	expected := `error="handler, err: user \"yanun\" not found" code=user.not_found cause="user \"yanun\" not found" findUser.user_id=123 findUser.query="name = yanun" findUser.password=[REDACTED] TestFormatLogfmt.empty="" stack="findUser@logfmt_test.go:10 TestFormatLogfmt@logfmt_test.go:14"`
+	if text != expected {
+		t.Errorf("Expected logfmt\n%s\ngot\n%s", expected, text)
+	}
+
+	if text := (LogfmtFormatter{MaxFrames: 1}).Format(err); !strings.HasSuffix(text, ` stack=findUser@logfmt_test.go:10`) {
+		t.Errorf("Expected a single frame, got '%s'", text)
+	}
+
+	if text := (LogfmtFormatter{MaxFrames: -1}).Format(err); strings.Contains(text, "stack=") {
+		t.Errorf("Expected no stack, got '%s'", text)
+	}
+
+	if text := FormatWith(err, LogfmtFormat); text != expected {
+		t.Errorf("Expected registered logfmt formatter, got '%s'", text)
+	}
+}
+
+func TestFormatLogfmtJoin(t *testing.T) {
+	err := WithCode(Join(New("first"), context.Canceled), "batch")
+
+	text := FormatLogfmt(err)
+	if !strings.HasPrefix(text, `error="first\ncontext canceled" code=batch stack=TestFormatLogfmtJoin@logfmt_test.go:`) ||
+		!strings.HasSuffix(text, ` join.0=first join.1="context canceled"`) {
+		t.Errorf("Expected logfmt of the join, got '%s'", text)
+	}
+
+	if text := FormatLogfmt(context.Canceled); text != `error="context canceled"` {
+		t.Errorf("Expected logfmt of a foreign error, got '%s'", text)
+	}
+
+	if text := FormatLogfmt(nil); text != "" {
+		t.Errorf("Expected empty string of nil error, got '%s'", text)
+	}
+}
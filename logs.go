@@ -9,6 +9,7 @@ import (
 // make errorStack implements tsf interface
 var (
 	_ logs.Error = (*errorStack)(nil)
+	_ logs.Error = (*joinError)(nil)
 	_ logs.Frame = (*frame)(nil)
 	_ logs.Attr  = (*attr)(nil)
 )
@@ -22,8 +23,9 @@ func (e *errorStack) Cause() error {
 }
 
 func (e *errorStack) Stack() []any {
-	frames := make([]any, 0, len(e.stack))
-	for _, frame := range e.stack {
+	stack := e.stack.frames()
+	frames := make([]any, 0, len(stack))
+	for _, frame := range stack {
 		frames = append(frames, frame)
 	}
 
@@ -39,10 +41,59 @@ func (e *errorStack) Attributes() []any {
 	return attrs
 }
 
+func (e *joinError) Message() string {
+	return e.Error()
+}
+
+// Cause returns the causes of every joined error, joined
+func (e *joinError) Cause() error {
+	causes := make([]error, 0, len(e.errs))
+	for _, err := range e.errs {
+		if le, ok := err.(logs.Error); ok {
+			causes = append(causes, le.Cause())
+			continue
+		}
+
+		causes = append(causes, err)
+	}
+
+	return &joinError{errs: causes}
+}
+
+func (e *joinError) Stack() []any {
+	stack := e.stack.frames()
+	frames := make([]any, 0, len(stack))
+	for _, frame := range stack {
+		frames = append(frames, frame)
+	}
+
+	return frames
+}
+
+// Attributes returns the fields of the joined error followed by the fields of every joined error
+func (e *joinError) Attributes() []any {
+	attrs := make([]any, 0, len(e.attr))
+	for _, attr := range e.attr {
+		attrs = append(attrs, attr)
+	}
+
+	for _, err := range e.errs {
+		if le, ok := err.(logs.Error); ok {
+			attrs = append(attrs, le.Attributes()...)
+		}
+	}
+
+	return attrs
+}
+
 func (f frame) Parameters() (file, function, line string) {
 	return f.File, f.Function, f.Line
 }
 
+func (f frame) Details() (pkg, receiver, function string) {
+	return f.Package, f.Receiver, f.FullFunction
+}
+
 func (a attr) Parameters() (key string, value any) {
 	buf := stringBuilderPool.Get().(*strings.Builder)
 	defer stringBuilderPool.Put(buf)
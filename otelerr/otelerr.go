@@ -0,0 +1,240 @@
+// Package otelerr records errors on OpenTelemetry trace spans.
+//
+// Record sets the status of the span, adds an "exception" event carrying the
+// message, the type and the stack trace of the error, and maps the fields of
+// the error to span attributes.
+//
+//	ctx, span := tracer.Start(ctx, "GetUser")
+//	defer span.End()
+//
+//	if err := getUser(ctx, id); err != nil {
+//		otelerr.Record(span, err)
+//		return err
+//	}
+//
+// ContextFields attaches the trace and span IDs of the context to the errors
+// created by errors.NewCtx, errors.WrapCtx and errors.ErrorfCtx.
+//
+//	errors.RegisterContextFields(otelerr.ContextFields)
+package otelerr
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yanun0323/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// EventName is the name of the event recording the error
+	EventName = "exception"
+
+	// CodeKey is the attribute key of the code of the error
+	CodeKey = attribute.Key("error.code")
+
+	messageKey    = attribute.Key("exception.message")
+	typeKey       = attribute.Key("exception.type")
+	stacktraceKey = attribute.Key("exception.stacktrace")
+)
+
+// Option configures the recording of an error
+type Option func(*options)
+
+type options struct {
+	prefix string
+	status bool
+}
+
+// WithAttributePrefix prefixes the keys of the span attributes mapped from the fields, e.g. "error."
+func WithAttributePrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithoutStatus records the error without setting the status of the span
+func WithoutStatus() Option {
+	return func(o *options) {
+		o.status = false
+	}
+}
+
+// Record records the error on the span
+//
+// The status of the span is set to Error with the message of the error. The
+// "exception" event carries the message, the type and the stack trace of the
+// error, and the code of the error under CodeKey if it has one. The type is the
+// Go type of the cause of the error (see errors.CauseOf), e.g. "*fs.PathError",
+// or "github.com/yanun0323/errors.Error" if it has none. The fields of the
+// error are set as span attributes, the last attached value wins for the same key.
+func Record(span trace.Span, err error, opts ...Option) {
+	if span == nil || err == nil || !span.IsRecording() {
+		return
+	}
+
+	o := options{status: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	code := errors.CodeOf(err)
+
+	event := []attribute.KeyValue{
+		messageKey.String(err.Error()),
+		typeKey.String(typeOf(err)),
+	}
+
+	if stack := stacktrace(err); stack != "" {
+		event = append(event, stacktraceKey.String(stack))
+	}
+
+	if code != "" {
+		event = append(event, CodeKey.String(string(code)))
+	}
+
+	span.AddEvent(EventName, trace.WithAttributes(event...))
+
+	if fields := errors.Fields(err); len(fields) != 0 {
+		attrs := make([]attribute.KeyValue, 0, len(fields)+1)
+		for _, f := range fields {
+			attrs = append(attrs, Attribute(o.prefix+f.Key, f.Value))
+		}
+
+		span.SetAttributes(attrs...)
+	}
+
+	if code != "" {
+		span.SetAttributes(CodeKey.String(string(code)))
+	}
+
+	if o.status {
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// RecordContext records the error on the span of the context, see Record
+func RecordContext(ctx context.Context, err error, opts ...Option) {
+	Record(trace.SpanFromContext(ctx), err, opts...)
+}
+
+// ContextFields returns the trace and span IDs of the span of the context as fields,
+// it can be registered with errors.RegisterContextFields
+func ContextFields(ctx context.Context) []any {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return []any{
+		"trace_id", sc.TraceID().String(),
+		"span_id", sc.SpanID().String(),
+	}
+}
+
+// Attribute converts the field into a span attribute with the type of its value
+//
+// Strings, booleans, integers, floats and their slices keep their types,
+// durations and other values are converted to strings.
+func Attribute(key string, value any) attribute.KeyValue {
+	k := attribute.Key(key)
+
+	switch v := value.(type) {
+	case string:
+		return k.String(v)
+	case bool:
+		return k.Bool(v)
+	case int:
+		return k.Int(v)
+	case int8:
+		return k.Int64(int64(v))
+	case int16:
+		return k.Int64(int64(v))
+	case int32:
+		return k.Int64(int64(v))
+	case int64:
+		return k.Int64(v)
+	case uint8:
+		return k.Int64(int64(v))
+	case uint16:
+		return k.Int64(int64(v))
+	case uint32:
+		return k.Int64(int64(v))
+	case float32:
+		return k.Float64(float64(v))
+	case float64:
+		return k.Float64(v)
+	case []string:
+		return k.StringSlice(v)
+	case []bool:
+		return k.BoolSlice(v)
+	case []int:
+		return k.IntSlice(v)
+	case []int64:
+		return k.Int64Slice(v)
+	case []float64:
+		return k.Float64Slice(v)
+	case time.Duration:
+		return k.String(v.String())
+	case fmt.Stringer:
+		return k.String(v.String())
+	case error:
+		return k.String(v.Error())
+	}
+
+	return k.String(fmt.Sprintf("%+v", value))
+}
+
+/*
+	########  ########  #### ##     ##    ###    ######## ########
+	##     ## ##     ##  ##  ##     ##   ## ##      ##    ##
+	##     ## ##     ##  ##  ##     ##  ##   ##     ##    ##
+	########  ########   ##  ##     ## ##     ##    ##    ######
+	##        ##   ##    ##   ##   ##  #########    ##    ##
+	##        ##    ##   ##    ## ##   ##     ##    ##    ##
+	##        ##     ## ####    ###    ##     ##    ##    ########
+*/
+
+// errorType is the exception type of the errors without a cause
+var errorType = reflect.TypeOf((*errors.Error)(nil)).Elem()
+
+// typeOf returns the exception type of the error, the Go type of its cause,
+// or the package-qualified name of errors.Error if it has no cause
+func typeOf(err error) string {
+	if cause := errors.CauseOf(err); cause != nil {
+		return fmt.Sprintf("%T", cause)
+	}
+
+	return errorType.PkgPath() + "." + errorType.Name()
+}
+
+// stacktrace returns the stack trace of the error in the format of a Go panic
+func stacktrace(err error) string {
+	frames := errors.StackOf(err)
+	if len(frames) == 0 {
+		return ""
+	}
+
+	var buf strings.Builder
+	for _, f := range frames {
+		function := f.FullFunction
+		if function == "" {
+			function = f.Function
+		}
+
+		buf.WriteString(function)
+		buf.WriteString("()\n\t")
+		buf.WriteString(f.File)
+		buf.WriteByte(':')
+		buf.WriteString(strconv.Itoa(f.Line))
+		buf.WriteByte('\n')
+	}
+
+	return buf.String()
+}
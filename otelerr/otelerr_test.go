@@ -0,0 +1,182 @@
+package otelerr
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yanun0323/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracer(t *testing.T) (*tracetest.InMemoryExporter, func(ctx context.Context, name string) (context.Context, func())) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	tracer := provider.Tracer("otelerr")
+	return exporter, func(ctx context.Context, name string) (context.Context, func()) {
+		ctx, span := tracer.Start(ctx, name)
+		return ctx, func() { span.End() }
+	}
+}
+
+func getUser() error {
+	return errors.WithCode(errors.New("user not found"), "user.not_found").
+		With("user_id", 123, "name", "yanun", "admin", false, "score", 9.5, "tags", []string{"a", "b"}, "elapsed", time.Second, "password", errors.Secret("s3cr3t"))
+}
+
+func TestRecord(t *testing.T) {
+	exporter, start := newTracer(t)
+
+	ctx, end := start(context.Background(), "GetUser")
+	RecordContext(ctx, errors.Wrap(getUser(), "handler"), WithAttributePrefix("app."))
+	end()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.Status.Code != codes.Error || span.Status.Description != "handler, err: user not found" {
+		t.Errorf("Expected error status, got %+v", span.Status)
+	}
+
+	attrs := attribute.NewSet(span.Attributes...)
+	expected := []attribute.KeyValue{
+		attribute.Int("app.user_id", 123),
+		attribute.String("app.name", "yanun"),
+		attribute.Bool("app.admin", false),
+		attribute.Float64("app.score", 9.5),
+		attribute.StringSlice("app.tags", []string{"a", "b"}),
+		attribute.String("app.elapsed", "1s"),
+		attribute.String("app.password", "[REDACTED]"),
+		CodeKey.String("user.not_found"),
+	}
+
+	for _, kv := range expected {
+		v, ok := attrs.Value(kv.Key)
+		if !ok || v != kv.Value {
+			t.Errorf("Expected attribute %s=%v, got %v", kv.Key, kv.Value.Emit(), v.Emit())
+		}
+	}
+
+	if len(span.Events) != 1 || span.Events[0].Name != EventName {
+		t.Fatalf("Expected an exception event, got %+v", span.Events)
+	}
+
+	event := attribute.NewSet(span.Events[0].Attributes...)
+	if v, _ := event.Value(messageKey); v.AsString() != "handler, err: user not found" {
+		t.Errorf("Expected exception message, got '%s'", v.AsString())
+	}
+
+	if v, _ := event.Value(typeKey); v.AsString() != "github.com/yanun0323/errors.Error" {
+		t.Errorf("Expected exception type 'github.com/yanun0323/errors.Error', got '%s'", v.AsString())
+	}
+
+	if v, _ := event.Value(CodeKey); v.AsString() != "user.not_found" {
+		t.Errorf("Expected exception code 'user.not_found', got '%s'", v.AsString())
+	}
+
+	stack, _ := event.Value(stacktraceKey)
+	for _, s := range []string{"otelerr.getUser()\n\t", "otelerr_test.go:", "otelerr.TestRecord()\n\t"} {
+		if !strings.Contains(stack.AsString(), s) {
+			t.Errorf("Expected stack trace to contain '%s', got '%s'", s, stack.AsString())
+		}
+	}
+}
+
+func TestRecordForeignError(t *testing.T) {
+	exporter, start := newTracer(t)
+
+	ctx, end := start(context.Background(), "Foreign")
+	RecordContext(ctx, context.Canceled, WithoutStatus())
+	RecordContext(ctx, nil)
+	end()
+
+	span := exporter.GetSpans()[0]
+	if span.Status.Code != codes.Unset {
+		t.Errorf("Expected unset status, got %+v", span.Status)
+	}
+
+	event := attribute.NewSet(span.Events[0].Attributes...)
+	if v, _ := event.Value(typeKey); v.AsString() != "*errors.errorString" {
+		t.Errorf("Expected exception type '*errors.errorString', got '%s'", v.AsString())
+	}
+
+	if _, ok := event.Value(stacktraceKey); ok {
+		t.Error("Expected no stack trace of a foreign error")
+	}
+}
+
+func TestRecordWrappedByForeignError(t *testing.T) {
+	exporter, start := newTracer(t)
+
+	ctx, end := start(context.Background(), "Wrapped")
+	RecordContext(ctx, fmt.Errorf("handler: %w", getUser()))
+	end()
+
+	event := attribute.NewSet(exporter.GetSpans()[0].Events[0].Attributes...)
+	if v, _ := event.Value(typeKey); v.AsString() != "github.com/yanun0323/errors.Error" {
+		t.Errorf("Expected exception type 'github.com/yanun0323/errors.Error', got '%s'", v.AsString())
+	}
+
+	if v, _ := event.Value(CodeKey); v.AsString() != "user.not_found" {
+		t.Errorf("Expected exception code 'user.not_found', got '%s'", v.AsString())
+	}
+
+	stack, _ := event.Value(stacktraceKey)
+	if !strings.Contains(stack.AsString(), "otelerr.getUser()\n\t") {
+		t.Errorf("Expected stack trace of the wrapped error, got '%s'", stack.AsString())
+	}
+}
+
+func TestRecordForeignCause(t *testing.T) {
+	exporter, start := newTracer(t)
+
+	_, e := os.Open("/not/exist")
+	ctx, end := start(context.Background(), "Cause")
+	RecordContext(ctx, errors.Wrap(fmt.Errorf("load config: %w", errors.Wrap(e, "open")), "start"))
+	RecordContext(ctx, errors.Join(errors.Wrap(context.DeadlineExceeded), errors.New("second")))
+	end()
+
+	events := exporter.GetSpans()[0].Events
+	for i, expected := range []string{"*fs.PathError", "context.deadlineExceededError"} {
+		event := attribute.NewSet(events[i].Attributes...)
+		if v, _ := event.Value(typeKey); v.AsString() != expected {
+			t.Errorf("Expected exception type '%s', got '%s'", expected, v.AsString())
+		}
+	}
+}
+
+func TestContextFields(t *testing.T) {
+	_, start := newTracer(t)
+
+	if fields := ContextFields(context.Background()); fields != nil {
+		t.Errorf("Expected no fields without span, got %v", fields)
+	}
+
+	errors.RegisterContextFields(ContextFields)
+
+	ctx, end := start(context.Background(), "Context")
+	defer end()
+
+	err := errors.NewCtx(ctx, "failed")
+	traceID, ok := errors.Lookup(err, "trace_id")
+	if !ok || len(traceID.(string)) != 32 {
+		t.Errorf("Expected trace_id field, got %v", traceID)
+	}
+
+	if _, ok := errors.Lookup(err, "span_id"); !ok {
+		t.Error("Expected span_id field")
+	}
+}
@@ -0,0 +1,132 @@
+package errors
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"slices"
+)
+
+// PanicError is the cause of the errors recovered from panics
+//
+// It keeps the original panic value, and unwraps to it when the value is an error,
+// so errors.Is and errors.As match the panicking error.
+//
+//	var pe *errors.PanicError
+//	if errors.As(err, &pe) {
+//		log.Println("recovered:", pe.Value)
+//	}
+type PanicError struct {
+	Value any
+}
+
+// Error implements the error interface
+func (p *PanicError) Error() string {
+	if err, ok := p.Value.(error); ok {
+		return "panic: " + err.Error()
+	}
+
+	return fmt.Sprintf("panic: %v", p.Value)
+}
+
+// Unwrap returns the panic value if it is an error
+func (p *PanicError) Unwrap() error {
+	if err, ok := p.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// Recover recovers a panic and stores it into errp as an Error, it must be called with defer
+//
+// The stack of the Error is captured at the panic site, and its cause is a
+// *PanicError holding the panic value. errp is not modified if there is no panic.
+//
+//	func handle() (err error) {
+//		defer errors.Recover(&err)
+//		...
+//	}
+func Recover(errp *error) {
+	if v := recover(); v != nil {
+		*errp = newPanicError(v)
+	}
+}
+
+// Go calls fn in a new goroutine, the returned channel receives the error returned
+// by fn, or the Error recovered from its panic, and is closed afterward.
+func Go(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		ch <- call(fn)
+	}()
+
+	return ch
+}
+
+// SafeGo calls fn in a new goroutine, the panic of fn is recovered and passed to handle
+//
+// The recovered Error is logged by the default slog.Logger if handle is nil.
+func SafeGo(fn func(), handle func(err error)) {
+	go func() {
+		err := call(func() error {
+			fn()
+			return nil
+		})
+
+		if err == nil {
+			return
+		}
+
+		if handle == nil {
+			slog.Default().Error("errors: recovered from panic", "error", err)
+			return
+		}
+
+		handle(err)
+	}()
+}
+
+// call calls fn and recovers its panic
+func call(fn func() error) (err error) {
+	defer Recover(&err)
+
+	return fn()
+}
+
+// newPanicError creates the Error of the recovered panic value,
+// it keeps the code and the fields of the value like Wrap does
+func newPanicError(v any) Error {
+	cause := &PanicError{Value: v}
+	e := &errorStack{
+		message: cause.Error(),
+		cause:   cause,
+		wrapped: cause.Unwrap(),
+		stack:   panicStack(loadStackConfig()),
+	}
+
+	if err, ok := e.wrapped.(*errorStack); ok && err != nil {
+		e.code = err.code
+		e.attr = slices.Clone(err.attr)
+		e.redaction = err.redaction
+	}
+
+	return e
+}
+
+// panicStack captures the stack of the panicking goroutine from the panic site,
+// it must be called by the deferred function which recovers the panic
+func panicStack(cfg *StackConfig) *stack {
+	pc := make([]uintptr, cfg.maxDepth()+_defaultMaxDepth)
+	pc = pc[:runtime.Callers(_defaultSkip, pc)]
+
+	for i, p := range pc {
+		if fn := runtime.FuncForPC(p - 1); fn != nil && fn.Name() == "runtime.gopanic" {
+			pc = pc[i+1:]
+			break
+		}
+	}
+
+	return newStack(pc, cfg)
+}
@@ -0,0 +1,114 @@
+package errors
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+)
+
+var errPanicSentinel = Sentinel("panic sentinel")
+
+func panicSite(v any) {
+	panic(v)
+}
+
+func recoverPanic(v any) (err error) {
+	defer Recover(&err)
+
+	panicSite(v)
+	return nil
+}
+
+func TestRecover(t *testing.T) {
+	err := recoverPanic("boom")
+	if err == nil {
+		t.Fatal("Expected recovered error")
+	}
+
+	if err.Error() != "panic: boom" {
+		t.Errorf("Expected message 'panic: boom', got '%s'", err.Error())
+	}
+
+	var pe *PanicError
+	if !As(err, &pe) || pe.Value != "boom" {
+		t.Errorf("Expected *PanicError with the panic value, got %v", pe)
+	}
+
+	functions := stackFunctions(err.(Error))
+	if len(functions) < 2 || functions[0] != "panicSite" || functions[1] != "recoverPanic" {
+		t.Errorf("Expected the stack captured at the panic site, got %v", functions)
+	}
+
+	if text := Format(err); !strings.Contains(text, "panic: boom") || !strings.Contains(text, "in panicSite") {
+		t.Errorf("Expected text format of the panic, got '%s'", text)
+	}
+
+	var nilErr error
+	func() {
+		defer Recover(&nilErr)
+	}()
+
+	if nilErr != nil {
+		t.Errorf("Expected no error without panic, got %v", nilErr)
+	}
+}
+
+func TestRecoverError(t *testing.T) {
+	err := recoverPanic(WithCode(Wrap(errPanicSentinel, "wrapped"), "panic.code").With("k1", "v1"))
+
+	if err.Error() != "panic: wrapped, err: panic sentinel" {
+		t.Errorf("Expected message of the panic error, got '%s'", err.Error())
+	}
+
+	if !Is(err, errPanicSentinel) {
+		t.Error("Expected recovered error to match the panicking error")
+	}
+
+	if CodeOf(err) != "panic.code" {
+		t.Errorf("Expected code 'panic.code', got '%s'", CodeOf(err))
+	}
+
+	if v, _ := Lookup(err, "k1"); v != "v1" {
+		t.Errorf("Expected field of the panicking error, got %v", v)
+	}
+
+	var re runtime.Error
+	err = recoverPanic(nil)
+	if !As(err, &re) {
+		t.Errorf("Expected runtime.Error of panic(nil), got %T", err)
+	}
+}
+
+func TestGo(t *testing.T) {
+	if err := <-Go(func() error { return nil }); err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+
+	if err := <-Go(func() error { return errPanicSentinel }); !Is(err, errPanicSentinel) {
+		t.Errorf("Expected returned error, got %v", err)
+	}
+
+	err := <-Go(func() error {
+		var m map[string]int
+		m["k"] = 1
+		return nil
+	})
+
+	var re runtime.Error
+	if !As(err, &re) {
+		t.Fatalf("Expected runtime.Error, got %T %v", err, err)
+	}
+
+	if functions := stackFunctions(err.(Error)); len(functions) == 0 || functions[0] != "func3" {
+		t.Errorf("Expected the stack captured at the panic site, got %v", functions)
+	}
+}
+
+func TestSafeGo(t *testing.T) {
+	ch := make(chan error, 1)
+	SafeGo(func() { panicSite("boom") }, func(err error) { ch <- err })
+
+	if err := <-ch; err == nil || err.Error() != "panic: boom" {
+		t.Errorf("Expected recovered panic, got %v", err)
+	}
+}
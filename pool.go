@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"bufio"
+	"bytes"
 	"strings"
 	"sync"
 )
@@ -11,4 +13,16 @@ var (
 			return &strings.Builder{}
 		},
 	}
+
+	bufferPool = sync.Pool{
+		New: func() any {
+			return &bytes.Buffer{}
+		},
+	}
+
+	bufioWriterPool = sync.Pool{
+		New: func() any {
+			return bufio.NewWriterSize(nil, 4096)
+		},
+	}
 )
@@ -0,0 +1,262 @@
+// Package problem renders errors as application/problem+json documents (RFC 9457).
+//
+// Only the members listed in the Config are exposed: the fields of the error
+// are added as extension members when their keys are allowed, and the stack
+// trace is never written to the response.
+//
+//	cfg := problem.Config{
+//		TypeURI: "https://example.com/problems/",
+//		Fields:  []string{"user_id"},
+//		Detail:  true,
+//	}
+//
+//	http.Handle("/users", cfg.Handler(func(w http.ResponseWriter, r *http.Request) error {
+//		return errors.WithCode(errors.New("user not found"), "user.not_found").With("user_id", 123)
+//	}))
+package problem
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/yanun0323/errors"
+)
+
+// ContentType is the media type of the problem document
+const ContentType = "application/problem+json"
+
+// Problem is a problem details document
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+// MarshalJSON implements the json.Marshaler interface,
+// the extension members are written at the top level of the document
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+
+	return json.Marshal(m)
+}
+
+var registry = struct {
+	sync.RWMutex
+	code     map[errors.Code]int
+	category map[string]int
+	sentinel []sentinel
+}{
+	code:     map[errors.Code]int{},
+	category: map[string]int{},
+}
+
+type sentinel struct {
+	err    error
+	status int
+}
+
+// Register maps the error code to the HTTP status code
+func Register(code errors.Code, status int) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.code[code] = status
+}
+
+// RegisterCategory maps every error code in the category to the HTTP status code
+func RegisterCategory(category string, status int) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.category[category] = status
+}
+
+// RegisterSentinel maps the errors matching the sentinel error (by errors.Is) to the HTTP status code
+func RegisterSentinel(err error, status int) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.sentinel = append(registry.sentinel, sentinel{err: err, status: status})
+}
+
+// StatusOf returns the HTTP status code of the error
+//
+// The status is resolved from the registered codes, categories and sentinel
+// errors, in that order. It returns http.StatusInternalServerError if nothing matches.
+func StatusOf(err error) int {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	if code := errors.CodeOf(err); code != "" {
+		if status, ok := registry.code[code]; ok {
+			return status
+		}
+
+		if status, ok := registry.category[code.Category()]; ok {
+			return status
+		}
+	}
+
+	for _, s := range registry.sentinel {
+		if errors.Is(err, s.err) {
+			return s.status
+		}
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Config configures how errors are rendered as problem documents
+//
+// The zero value is safe to use: it exposes the status and the code of the
+// error only.
+type Config struct {
+	// TypeURI is the prefix of the type member, the code of the error is appended to it.
+	// The type is "about:blank" if TypeURI is empty or the error has no code.
+	TypeURI string
+
+	// Fields is the allow-list of the field keys exposed as extension members.
+	Fields []string
+
+	// Detail exposes the message of the error as the detail member.
+	Detail bool
+
+	// Log is called with every error written by the Handler.
+	// The full text format of the error is written by the standard logger if Log is nil.
+	Log func(r *http.Request, err error)
+}
+
+// Problem returns the problem document of the error
+func (c Config) Problem(r *http.Request, err error) *Problem {
+	status := StatusOf(err)
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+
+	if r != nil {
+		p.Instance = r.URL.Path
+	}
+
+	if c.Detail {
+		p.Detail = err.Error()
+	}
+
+	if code := errors.CodeOf(err); code != "" {
+		if c.TypeURI != "" {
+			p.Type = c.TypeURI + string(code)
+		}
+
+		p.Extensions = map[string]any{"code": code}
+	}
+
+	if len(c.Fields) == 0 {
+		return p
+	}
+
+	for _, f := range errors.Fields(err) {
+		if !c.allowed(f.Key) {
+			continue
+		}
+
+		if p.Extensions == nil {
+			p.Extensions = map[string]any{}
+		}
+
+		p.Extensions[f.Key] = f.Value
+	}
+
+	return p
+}
+
+// Write writes the problem document of the error to the response
+func (c Config) Write(w http.ResponseWriter, r *http.Request, err error) {
+	p := c.Problem(r, err)
+
+	data, e := json.Marshal(p)
+	if e != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(data)
+}
+
+// Handler adapts the handler into an http.Handler,
+// the returned errors are logged and written as problem documents
+func (c Config) Handler(h HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := h(w, r)
+		if err == nil {
+			return
+		}
+
+		c.log(r, err)
+		c.Write(w, r, err)
+	})
+}
+
+// HandlerFunc is an http handler which returns an error
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP implements the http.Handler interface with the zero Config
+func (h HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	Config{}.Handler(h).ServeHTTP(w, r)
+}
+
+// Write writes the problem document of the error to the response with the zero Config
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	Config{}.Write(w, r, err)
+}
+
+/*
+	########  ########  #### ##     ##    ###    ######## ########
+	##     ## ##     ##  ##  ##     ##   ## ##      ##    ##
+	##     ## ##     ##  ##  ##     ##  ##   ##     ##    ##
+	########  ########   ##  ##     ## ##     ##    ##    ######
+	##        ##   ##    ##   ##   ##  #########    ##    ##
+	##        ##    ##   ##    ## ##   ##     ##    ##    ##
+	##        ##     ## ####    ###    ##     ##    ##    ########
+*/
+
+func (c Config) allowed(key string) bool {
+	for _, k := range c.Fields {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c Config) log(r *http.Request, err error) {
+	if c.Log != nil {
+		c.Log(r, err)
+		return
+	}
+
+	log.Printf("%s %s%s", r.Method, r.URL.Path, errors.Format(err))
+}
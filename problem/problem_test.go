@@ -0,0 +1,125 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yanun0323/errors"
+)
+
+var errUserNotFound = errors.New("user not found")
+
+func init() {
+	Register("auth.forbidden", http.StatusForbidden)
+	RegisterCategory("auth", http.StatusUnauthorized)
+	RegisterSentinel(errUserNotFound, http.StatusNotFound)
+}
+
+func TestStatusOf(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{"code", errors.WithCode(errors.New("forbidden"), "auth.forbidden"), http.StatusForbidden},
+		{"category", errors.WithCode(errors.New("expired"), "auth.token_expired"), http.StatusUnauthorized},
+		{"sentinel", errors.Wrap(errUserNotFound, "get user"), http.StatusNotFound},
+		{"unknown", errors.New("unknown"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if status := StatusOf(tc.err); status != tc.expected {
+				t.Errorf("Expected status %d, got %d", tc.expected, status)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	var logged string
+	cfg := Config{
+		TypeURI: "https://example.com/problems/",
+		Fields:  []string{"user_id"},
+		Detail:  true,
+		Log: func(_ *http.Request, err error) {
+			logged = errors.Format(err)
+		},
+	}
+
+	h := cfg.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.WithCode(errors.New("token expired"), "auth.token_expired").With("user_id", 123, "secret", "s3cr3t")
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/123?debug=1", nil))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Expected content type '%s', got '%s'", ContentType, ct)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("unmarshal error: %v", err)
+	}
+
+	expected := map[string]any{
+		"type":     "https://example.com/problems/auth.token_expired",
+		"title":    "Unauthorized",
+		"status":   float64(401),
+		"detail":   "token expired",
+		"instance": "/users/123",
+		"code":     "auth.token_expired",
+		"user_id":  float64(123),
+	}
+
+	if len(doc) != len(expected) {
+		t.Errorf("Expected %d members, got %v", len(expected), doc)
+	}
+
+	for k, v := range expected {
+		if doc[k] != v {
+			t.Errorf("Expected member '%s' to be %v, got %v", k, v, doc[k])
+		}
+	}
+
+	if !strings.Contains(logged, "secret: s3cr3t") || !strings.Contains(logged, "stack:") {
+		t.Errorf("Expected the full error to be logged, got '%s'", logged)
+	}
+}
+
+func TestHandlerFunc(t *testing.T) {
+	h := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		if r.URL.Path == "/ok" {
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+
+		return errors.Wrap(errUserNotFound, "internal detail").With("user_id", 123)
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/123", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	body := rec.Body.String()
+	expected := `{"instance":"/users/123","status":404,"title":"Not Found","type":"about:blank"}`
+	if body != expected {
+		t.Errorf("Expected body '%s', got '%s'", expected, body)
+	}
+}
@@ -0,0 +1,143 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+const _redacted = "[REDACTED]"
+
+// Redacted is a field value which is masked in every output of the error
+//
+// It is rendered as "[REDACTED]" by the text, colorized and JSON formats,
+// log/slog and the logs package, only Value returns the original value.
+//
+//	err := errors.New("login failed").With("password", errors.Secret(password))
+type Redacted struct {
+	value any
+}
+
+// Secret returns the value wrapped as a Redacted value
+func Secret(value any) Redacted {
+	if r, ok := value.(Redacted); ok {
+		return r
+	}
+
+	return Redacted{value: value}
+}
+
+// Value returns the original value
+func (r Redacted) Value() any {
+	return r.value
+}
+
+// String implements the fmt.Stringer interface
+func (r Redacted) String() string {
+	return _redacted
+}
+
+// GoString implements the fmt.GoStringer interface
+func (r Redacted) GoString() string {
+	return _redacted
+}
+
+// Format implements the fmt.Formatter interface, every verb renders the masked value
+func (r Redacted) Format(f fmt.State, _ rune) {
+	_, _ = f.Write([]byte(_redacted))
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (r Redacted) MarshalJSON() ([]byte, error) {
+	return json.Marshal(_redacted)
+}
+
+// LogValue implements the slog.LogValuer interface
+func (r Redacted) LogValue() slog.Value {
+	return slog.StringValue(_redacted)
+}
+
+// Redaction is a policy which masks the values of fields by their keys
+//
+// A field is masked if its key, or the last part of a dotted group key,
+// matches one of the Keys case-insensitively or matches the Pattern.
+type Redaction struct {
+	// Keys are the keys of the masked fields, e.g. "password" or "token".
+	Keys []string
+
+	// Pattern masks the fields whose key matches it.
+	Pattern *regexp.Regexp
+}
+
+var (
+	_redaction atomic.Pointer[Redaction]
+)
+
+// SetRedaction sets the package-level Redaction applied to the fields of every error
+//
+// The policy is applied when the fields are attached, so it is safe to call
+// concurrently with creating and formatting errors. The fields attached
+// before the policy was set are not masked by it.
+func SetRedaction(r Redaction) {
+	_redaction.Store(r.clone())
+}
+
+// CurrentRedaction returns the package-level Redaction
+func CurrentRedaction() Redaction {
+	return *loadRedaction().clone()
+}
+
+func loadRedaction() *Redaction {
+	if r := _redaction.Load(); r != nil {
+		return r
+	}
+
+	_redaction.CompareAndSwap(nil, &Redaction{})
+	return _redaction.Load()
+}
+
+func (r Redaction) clone() *Redaction {
+	r.Keys = slices.Clone(r.Keys)
+	return &r
+}
+
+// match reports whether the field with the key should be masked
+func (r *Redaction) match(key string) bool {
+	if r == nil {
+		return false
+	}
+
+	short := key
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		short = key[i+1:]
+	}
+
+	for _, k := range r.Keys {
+		if strings.EqualFold(k, key) || strings.EqualFold(k, short) {
+			return true
+		}
+	}
+
+	return r.Pattern != nil && r.Pattern.MatchString(key)
+}
+
+// redactAttrs masks the values of the attributes matching the package-level
+// Redaction or the given one, the attributes are modified in place
+func redactAttrs(r *Redaction, attrs []attr) []attr {
+	global := loadRedaction()
+	for i, a := range attrs {
+		if _, ok := a.Value.(Redacted); ok {
+			continue
+		}
+
+		if global.match(a.Key) || r.match(a.Key) {
+			attrs[i].Value = Secret(a.Value)
+		}
+	}
+
+	return attrs
+}
@@ -0,0 +1,129 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/yanun0323/errors/internal/logs"
+)
+
+const secretValue = "s3cr3t-value"
+
+// renderings returns every output of the error
+func renderings(t *testing.T, err error) map[string]string {
+	t.Helper()
+
+	data, e := json.Marshal(err)
+	if e != nil {
+		t.Fatalf("marshal error: %v", e)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "err", err)
+
+	var params strings.Builder
+	if le, ok := err.(logs.Error); ok {
+		for _, a := range le.Attributes() {
+			key, value := a.(logs.Attr).Parameters()
+			fmt.Fprintf(&params, "%s=%v %+v %#v\n", key, value, value, value)
+		}
+	}
+
+	var fields strings.Builder
+	for _, f := range Fields(err) {
+		fmt.Fprintf(&fields, "%s=%v %s\n", f.Key, f.Value, f.Value)
+	}
+
+	return map[string]string{
+		"message":   err.Error(),
+		"text":      Format(err),
+		"json":      FormatJson(err),
+		"colorized": FormatColorized(err),
+		"%v":        fmt.Sprintf("%v", err),
+		"%+v":       fmt.Sprintf("%+v", err),
+		"%#v":       fmt.Sprintf("%#v", err),
+		"marshal":   string(data),
+		"slog":      buf.String(),
+		"logs":      params.String(),
+		"fields":    fields.String(),
+	}
+}
+
+func assertRedacted(t *testing.T, err error) {
+	t.Helper()
+
+	for name, s := range renderings(t, err) {
+		if strings.Contains(s, secretValue) {
+			t.Errorf("Expected %s output not to contain the secret, got '%s'", name, s)
+		}
+	}
+
+	if text := Format(err); !strings.Contains(text, _redacted) {
+		t.Errorf("Expected text output to contain '%s', got '%s'", _redacted, text)
+	}
+}
+
+func TestSecret(t *testing.T) {
+	err := Wrap(New("login failed").With("password", Secret(secretValue)), "wrapped").With("user", "yanun")
+	assertRedacted(t, err)
+
+	assertRedacted(t, Join(err, New("other")))
+	assertRedacted(t, Errorf("login %v: %w", Secret(secretValue), New("failed").With("token", Secret(secretValue))))
+
+	v, _ := Lookup(err, "password")
+	if r, ok := v.(Redacted); !ok || r.Value() != secretValue {
+		t.Errorf("Expected the original value from Redacted, got %v", v)
+	}
+
+	if Secret(Secret(secretValue)).Value() != secretValue {
+		t.Error("Expected Secret not to wrap a Redacted value twice")
+	}
+}
+
+func TestSetRedaction(t *testing.T) {
+	defer SetRedaction(Redaction{})
+
+	SetRedaction(Redaction{Keys: []string{"Password"}, Pattern: regexp.MustCompile(`(?i)token$`)})
+
+	err := New("login failed").With(
+		"password", secretValue,
+		"access_token", secretValue,
+		Group("db", String("password", secretValue)),
+	).WithMap(map[string]any{"PASSWORD": secretValue})
+
+	assertRedacted(t, Wrap(err, "wrapped"))
+
+	rebuilt, e := FromJSON([]byte(FormatJson(err)))
+	if e != nil {
+		t.Fatalf("FromJSON error: %v", e)
+	}
+	assertRedacted(t, rebuilt)
+
+	if r := CurrentRedaction(); len(r.Keys) != 1 || r.Keys[0] != "Password" {
+		t.Errorf("Expected the current redaction, got %v", r)
+	}
+}
+
+func TestTemplateWithRedaction(t *testing.T) {
+	tmp := NewTemplate("email", secretValue).WithRedaction(Redaction{Keys: []string{"email", "card"}})
+
+	err := tmp.New("payment failed").With("card", secretValue, "amount", 100)
+	assertRedacted(t, err)
+
+	if v, _ := Lookup(err, "amount"); v != 100 {
+		t.Errorf("Expected the amount not to be masked, got %v", v)
+	}
+
+	assertRedacted(t, tmp.Wrap(New("declined"), "payment").With("card", secretValue))
+	assertRedacted(t, Wrap(tmp.New("payment failed")).With("card", secretValue))
+	assertRedacted(t, tmp.Errorf("%w and %w", New("a"), New("b")).With("card", secretValue))
+
+	if text := Format(New("other").With("card", "visible")); !strings.Contains(text, "card: visible") {
+		t.Errorf("Expected errors without the template not to be masked, got '%s'", text)
+	}
+}
@@ -0,0 +1,129 @@
+package errors
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Backoff returns the delay before the given retry, the first retry is 1
+type Backoff func(retry int) time.Duration
+
+// ConstantBackoff waits the same delay before every retry
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff doubles the delay before every retry from initial, up to limit
+func ExponentialBackoff(initial, limit time.Duration) Backoff {
+	return func(retry int) time.Duration {
+		delay := initial
+		for i := 1; i < retry && delay < limit; i++ {
+			delay *= 2
+		}
+
+		return min(delay, limit)
+	}
+}
+
+// WithJitter returns a Backoff which randomizes the delay of b by up to the given
+// fraction of it, e.g. 0.2 for ±20%
+func (b Backoff) WithJitter(fraction float64) Backoff {
+	return func(retry int) time.Duration {
+		delay := b(retry)
+		jitter := time.Duration(float64(delay) * fraction * (2*rand.Float64() - 1))
+
+		return max(delay+jitter, 0)
+	}
+}
+
+// RetryPolicy decides how Retry calls its function
+//
+// The zero value makes 3 attempts with an exponential backoff from 100ms up to 10s,
+// and retries the errors reported by IsRetryable.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts, including the first one
+	Attempts int
+
+	// Backoff returns the delay before every retry,
+	// a longer retry-after duration of the error is honored
+	Backoff Backoff
+
+	// Retryable reports whether the error is worth retrying
+	Retryable func(err error) bool
+}
+
+// Retry calls fn until it succeeds, its error is not retryable, the attempts of
+// the policy run out, or the context is done
+//
+// The returned Error is created like Join, every attempt error is a branch with
+// an "attempt" field starting at 1. The error of the context is the last branch
+// if the context is done while waiting. It returns nil if an attempt succeeds.
+//
+//	err := errors.Retry(ctx, errors.RetryPolicy{Attempts: 5}, func(ctx context.Context) error {
+//		return client.Call(ctx, req)
+//	})
+func Retry(ctx context.Context, policy RetryPolicy, fn func(ctx context.Context) error) Error {
+	policy = policy.normalize()
+
+	var errs []error
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, wrap(err, "", 1, false).With("attempt", attempt))
+
+		if attempt >= policy.Attempts || !policy.Retryable(err) {
+			break
+		}
+
+		delay := policy.Backoff(attempt)
+		if d, ok := RetryAfter(err); ok && d > delay {
+			delay = d
+		}
+
+		if err := wait(ctx, delay); err != nil {
+			errs = append(errs, err)
+			break
+		}
+	}
+
+	return &joinError{
+		errs:  errs,
+		stack: captureStack(loadStackConfig(), 0),
+	}
+}
+
+// normalize returns the policy with the defaults of the zero values
+func (p RetryPolicy) normalize() RetryPolicy {
+	if p.Attempts <= 0 {
+		p.Attempts = 3
+	}
+
+	if p.Backoff == nil {
+		p.Backoff = ExponentialBackoff(100*time.Millisecond, 10*time.Second)
+	}
+
+	if p.Retryable == nil {
+		p.Retryable = IsRetryable
+	}
+
+	return p
+}
+
+// wait waits for the delay, it returns the cause of the context if it is done first
+func wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
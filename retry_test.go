@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestClass(t *testing.T) {
+	err := Wrap(WithClass(New("unavailable"), Temporary), "call")
+	if !IsRetryable(err) || !IsTemporary(err) || IsTimeout(err) {
+		t.Errorf("Expected temporary error, got %s", ClassOf(err))
+	}
+
+	err = NewTemplate().WithRetryAfter(time.Second).New("rate limited")
+	if d, ok := RetryAfter(Wrap(err, "call")); !ok || d != time.Second || ClassOf(err) != Retryable {
+		t.Errorf("Expected retryable after 1s, got %s %v", ClassOf(err), d)
+	}
+
+	if !IsTimeout(Wrap(context.DeadlineExceeded, "call")) || IsRetryable(Wrap(context.Canceled, "call")) {
+		t.Error("Expected the classification of the context errors")
+	}
+
+	var netErr net.Error = &net.DNSError{Err: "timeout", IsTimeout: true}
+	if !IsTimeout(Join(New("first"), Errorf("dial: %w", netErr))) {
+		t.Error("Expected timeout of the joined net.Error")
+	}
+
+	if err := WithClass(net.ErrClosed, Temporary); !IsTemporary(err) || !Is(err, net.ErrClosed) || WithClass(nil, Temporary) != nil {
+		t.Errorf("Expected the foreign error wrapped and classified, got %v", err)
+	}
+
+	if IsRetryable(New("failed")) || IsRetryable(nil) {
+		t.Error("Expected unclassified error not retryable")
+	}
+
+	err = Wrap(WithRetryAfter(WithClass(New("slow"), Timeout|Temporary), time.Minute), "call")
+	if (Temporary | Timeout | Retryable).String() != "retryable|temporary|timeout" {
+		t.Errorf("Expected class names, got '%s'", (Temporary | Timeout | Retryable).String())
+	}
+
+	rebuilt, e := FromJSON([]byte(FormatJson(err)))
+	if e != nil {
+		t.Fatalf("Expected rebuilt error, got %v", e)
+	}
+
+	if d, _ := RetryAfter(rebuilt); ClassOf(rebuilt) != Retryable|Temporary|Timeout || d != time.Minute {
+		t.Errorf("Expected rebuilt classification, got %s %v", ClassOf(rebuilt), d)
+	}
+
+	var class Class
+	if e := json.Unmarshal([]byte(`"timeout|unknown"`), &class); e == nil {
+		t.Error("Expected error of an unknown class")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{Attempts: 4, Backoff: ConstantBackoff(time.Millisecond)}
+
+	calls := 0
+	err := Retry(context.Background(), policy, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return WithClass(New("unavailable"), Temporary)
+		}
+		return nil
+	})
+
+	if err != nil || calls != 3 {
+		t.Errorf("Expected success on the 3rd attempt, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	err = Retry(context.Background(), policy, func(context.Context) error {
+		calls++
+		return WithClass(New("unavailable"), Temporary)
+	})
+
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	if calls != 4 || len(errs) != 4 {
+		t.Fatalf("Expected 4 attempt errors, got %d after %d calls", len(errs), calls)
+	}
+
+	for i, err := range errs {
+		if v, _ := Lookup(err, "attempt"); v != i+1 {
+			t.Errorf("Expected attempt field %d, got %v", i+1, v)
+		}
+	}
+
+	calls = 0
+	err = Retry(context.Background(), policy, func(context.Context) error {
+		calls++
+		return errPanicSentinel
+	})
+
+	if calls != 1 || !Is(err, errPanicSentinel) {
+		t.Errorf("Expected no retry of an unclassified error, got %v after %d calls", err, calls)
+	}
+
+	if functions := stackFunctions(err.(interface{ Unwrap() []error }).Unwrap()[0].(Error)); len(functions) == 0 || functions[0] != "TestRetry" {
+		t.Errorf("Expected the attempt error wrapped at Retry, got %v", functions)
+	}
+}
+
+func TestRetryContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := Retry(ctx, RetryPolicy{Attempts: 10}, func(context.Context) error {
+		return WithRetryAfter(New("rate limited"), time.Hour)
+	})
+
+	if time.Since(start) > time.Second {
+		t.Error("Expected Retry to return when the context is done")
+	}
+
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(errs) != 2 || errs[1] != context.DeadlineExceeded {
+		t.Errorf("Expected the attempt error and the context error, got %v", errs)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	b := ExponentialBackoff(100*time.Millisecond, time.Second)
+	for retry, expected := range map[int]time.Duration{1: 100 * time.Millisecond, 3: 400 * time.Millisecond, 10: time.Second} {
+		if d := b(retry); d != expected {
+			t.Errorf("Expected delay %v of retry %d, got %v", expected, retry, d)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		if d := ConstantBackoff(time.Second).WithJitter(0.2)(1); d < 800*time.Millisecond || d > 1200*time.Millisecond {
+			t.Fatalf("Expected delay within 20%% of 1s, got %v", d)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package errors
+
+import "reflect"
+
+// sentinel is the cause of the errors created by Sentinel and Define,
+// it is compared by identity instead of by message
+type sentinel struct {
+	message string
+	code    Code
+}
+
+func (s *sentinel) Error() string {
+	return s.message
+}
+
+// Sentinel creates an error which is compared by identity
+//
+// Unlike New, two sentinel errors with the same message are not the same error,
+// Is reports true only for the sentinel itself and the errors wrapping it.
+//
+//	var ErrNotFound = errors.Sentinel("not found")
+//
+//	errors.Is(errors.Wrap(ErrNotFound, "get user"), ErrNotFound) // true
+//	errors.Is(errors.New("not found"), ErrNotFound)              // false
+func Sentinel(text string) Error {
+	return newSentinel(text, "")
+}
+
+// Define creates a sentinel error with a code
+//
+// Is reports true for the sentinel itself, the errors wrapping it, and every
+// error carrying the same code, e.g. an error rebuilt by FromJSON.
+//
+//	var ErrTokenExpired = errors.Define("auth.token_expired", "token expired")
+//
+//	errors.Is(errors.WithCode(errors.New("expired"), "auth.token_expired"), ErrTokenExpired) // true
+func Define(code Code, text string) Error {
+	return newSentinel(text, code)
+}
+
+func newSentinel(text string, code Code) Error {
+	return &errorStack{
+		message: text,
+		code:    code,
+		cause:   &sentinel{message: text, code: code},
+		stack:   captureStack(loadStackConfig(), 1),
+	}
+}
+
+// matcher matches the errors in a chain against a target
+type matcher struct {
+	key  error
+	code Code
+}
+
+// newMatcher returns the matcher of the target
+//
+// The key of an Error is its root cause, so errors created by New are matched
+// by message and errors created by Sentinel or Define are matched by identity.
+func newMatcher(target error) matcher {
+	m := matcher{key: target}
+	if e, ok := target.(*errorStack); ok && e != nil && e.cause != nil {
+		m.key = e.cause
+	}
+
+	if s, ok := m.key.(*sentinel); ok {
+		m.code = s.code
+	}
+
+	return m
+}
+
+// match walks every wrapped layer and joined branch of err
+func (m matcher) match(err error) bool {
+	for err != nil {
+		if equal(err, m.key) {
+			return true
+		}
+
+		switch e := err.(type) {
+		case *errorStack:
+			if e == nil {
+				return false
+			}
+
+			if m.code != "" && e.code == m.code || e.cause != nil && equal(e.cause, m.key) {
+				return true
+			}
+
+			if e.wrapped != nil {
+				err = e.wrapped
+			} else {
+				err = e.cause
+			}
+			continue
+		case *joinError:
+			if m.code != "" && e.code == m.code {
+				return true
+			}
+		}
+
+		if x, ok := err.(interface{ Is(error) bool }); ok && x.Is(m.key) {
+			return true
+		}
+
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, err := range u.Unwrap() {
+				if m.match(err) {
+					return true
+				}
+			}
+			return false
+		case unwrap:
+			err = u.Unwrap()
+		default:
+			return false
+		}
+	}
+
+	return false
+}
+
+// equal reports whether the errors are equal, without panicking on incomparable errors
+func equal(err, target error) bool {
+	return reflect.TypeOf(target).Comparable() && err == target
+}
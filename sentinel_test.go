@@ -0,0 +1,70 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+)
+
+var (
+	errSentinelNotFound = Sentinel("not found")
+	errSentinelExpired  = Define("auth.token_expired", "token expired")
+)
+
+func TestSentinel(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		err      error
+		target   error
+		expected bool
+	}{
+		{"itself", errSentinelNotFound, errSentinelNotFound, true},
+		{"wrapped", Wrap(errSentinelNotFound, "get user"), errSentinelNotFound, true},
+		{"with fields", errSentinelNotFound.With("k1", "v1"), errSentinelNotFound, true},
+		{"errorf", Errorf("get user: %w", errSentinelNotFound), errSentinelNotFound, true},
+		{"same message", New("not found"), errSentinelNotFound, false},
+		{"another sentinel", Sentinel("not found"), errSentinelNotFound, false},
+		{"sentinel to message", errSentinelNotFound, New("not found"), false},
+		{"joined", Join(New("other"), Wrap(errSentinelNotFound)), errSentinelNotFound, true},
+		{"foreign wrapper", Wrap(&foreignError{message: "foreign", err: errSentinelNotFound}), errSentinelNotFound, true},
+		{"defined", Wrap(errSentinelExpired, "login"), errSentinelExpired, true},
+		{"defined by code", WithCode(New("expired"), "auth.token_expired"), errSentinelExpired, true},
+		{"defined by inner code", WithCode(Wrap(WithCode(New("expired"), "auth.token_expired")), "login.failed"), errSentinelExpired, true},
+		{"defined by joined code", WithCode(Join(New("a")), "auth.token_expired"), errSentinelExpired, true},
+		{"defined other code", WithCode(New("token expired"), "auth.other"), errSentinelExpired, false},
+		{"message equality", Wrap(New("message"), "wrapped"), New("message"), true},
+		{"foreign target", Wrap(fmt.Errorf("read: %w", io.EOF)), io.EOF, true},
+		{"std target", Wrap(context.Canceled), context.Canceled, true},
+		{"nil", nil, errSentinelNotFound, false},
+		{"nil target", errSentinelNotFound, nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if Is(tc.err, tc.target) != tc.expected {
+				t.Errorf("Expected Is to be %v", tc.expected)
+			}
+		})
+	}
+}
+
+func TestSentinelJSON(t *testing.T) {
+	err, e := FromJSON([]byte(FormatJson(Wrap(errSentinelExpired, "login"))))
+	if e != nil {
+		t.Fatalf("FromJSON error: %v", e)
+	}
+
+	if !Is(err, errSentinelExpired) {
+		t.Error("Expected rebuilt error to match the defined error by code")
+	}
+
+	err, e = FromJSON([]byte(FormatJson(Wrap(errSentinelNotFound, "get user"))))
+	if e != nil {
+		t.Fatalf("FromJSON error: %v", e)
+	}
+
+	if Is(err, errSentinelNotFound) {
+		t.Error("Expected rebuilt error not to match the sentinel by identity")
+	}
+}
@@ -0,0 +1,136 @@
+package errors
+
+import (
+	"context"
+	"log/slog"
+)
+
+// make errorStack implements slog.LogValuer interface
+var (
+	_ slog.LogValuer = (*errorStack)(nil)
+	_ slog.Handler   = (*slogHandler)(nil)
+)
+
+// LogValue implements the slog.LogValuer interface
+//
+// The error is expanded into a group containing the message, the cause,
+// the fields grouped by the function that attached them and the stack frames.
+func (e *errorStack) LogValue() slog.Value {
+	if e == nil {
+		return slog.Value{}
+	}
+
+	return e.logValue(e.message)
+}
+
+func (e *errorStack) logValue(message string) slog.Value {
+	attrs := make([]slog.Attr, 0, 4)
+	attrs = append(attrs, slog.String("message", message))
+
+	if e.code != "" {
+		attrs = append(attrs, slog.String("code", string(e.code)))
+	}
+
+	if e.cause != nil {
+		attrs = append(attrs, slog.String("cause", e.cause.Error()))
+	}
+
+	if len(e.attr) != 0 {
+		attrFunctions, attrMap := groupAttrs(e.attr, false)
+		fields := make([]slog.Attr, 0, len(attrFunctions))
+		for _, key := range attrFunctions {
+			funcName := key
+			if funcName == "" {
+				funcName = "unknown"
+			}
+
+			values := make([]slog.Attr, 0, len(attrMap[key]))
+			for _, a := range attrMap[key] {
+				values = append(values, slog.Any(a.Key, a.Value))
+			}
+
+			fields = append(fields, slog.Attr{Key: funcName, Value: slog.GroupValue(values...)})
+		}
+
+		attrs = append(attrs, slog.Attr{Key: "field", Value: slog.GroupValue(fields...)})
+	}
+
+	if stack := e.stack.frames(); len(stack) != 0 {
+		frames := make([]string, 0, len(stack))
+		for _, f := range stack {
+			frames = append(frames, f.FormatText())
+		}
+
+		attrs = append(attrs, slog.Any("stack", frames))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// SlogReplaceAttr is a slog.HandlerOptions.ReplaceAttr function which expands
+// errors that wrap an Error (e.g. created by fmt.Errorf with '%w') in the same
+// way as an Error is expanded by its LogValue method.
+//
+//	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+//		ReplaceAttr: errors.SlogReplaceAttr,
+//	}))
+func SlogReplaceAttr(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() != slog.KindAny {
+		return a
+	}
+
+	err, ok := a.Value.Any().(error)
+	if !ok || err == nil {
+		return a
+	}
+
+	if e, ok := err.(*errorStack); ok {
+		return slog.Attr{Key: a.Key, Value: e.LogValue()}
+	}
+
+	var e *errorStack
+	if As(err, &e) && e != nil {
+		return slog.Attr{Key: a.Key, Value: e.logValue(err.Error())}
+	}
+
+	return a
+}
+
+// NewSlogHandler returns a slog.Handler which expands every error attribute
+// with SlogReplaceAttr before passing the record to h.
+//
+// It is useful when the ReplaceAttr option of h cannot be configured.
+func NewSlogHandler(h slog.Handler) slog.Handler {
+	return &slogHandler{handler: h}
+}
+
+type slogHandler struct {
+	handler slog.Handler
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	record := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		record.AddAttrs(SlogReplaceAttr(nil, a))
+		return true
+	})
+
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	replaced := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		replaced = append(replaced, SlogReplaceAttr(nil, a))
+	}
+
+	return &slogHandler{handler: h.handler.WithAttrs(replaced)}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{handler: h.handler.WithGroup(name)}
+}
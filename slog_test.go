@@ -0,0 +1,109 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+)
+
+func TestLogValue(t *testing.T) {
+	err := New("test error").With("k1", "v1", "k2", 2)
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+	logger.Error("failed", "err", err)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("unmarshal log record: %v", err)
+	}
+
+	e, ok := record["err"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected err group, got %v", record["err"])
+	}
+
+	if e["message"] != "test error" {
+		t.Errorf("expected message 'test error', got %v", e["message"])
+	}
+
+	if e["cause"] != "test error" {
+		t.Errorf("expected cause 'test error', got %v", e["cause"])
+	}
+
+	field, ok := e["field"].(map[string]any)["TestLogValue"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected field group of TestLogValue, got %v", e["field"])
+	}
+
+	if field["k1"] != "v1" || field["k2"] != float64(2) {
+		t.Errorf("unexpected fields: %v", field)
+	}
+
+	stack, ok := e["stack"].([]any)
+	if !ok || len(stack) == 0 {
+		t.Fatalf("expected stack frames, got %v", e["stack"])
+	}
+
+	if !containsString(stack[0].(string), "in TestLogValue") {
+		t.Errorf("expected first frame in TestLogValue, got %v", stack[0])
+	}
+}
+
+func TestSlogReplaceAttr(t *testing.T) {
+	err := &foreignError{message: "outer: inner", err: New("inner").With("k1", "v1")}
+
+	a := SlogReplaceAttr(nil, slog.Any("err", err))
+	if a.Value.Kind() != slog.KindGroup {
+		t.Fatalf("expected group value, got %v", a.Value.Kind())
+	}
+
+	group := a.Value.Group()
+	if group[0].Key != "message" || group[0].Value.String() != "outer: inner" {
+		t.Errorf("expected message 'outer: inner', got %v", group[0])
+	}
+
+	plain := SlogReplaceAttr(nil, slog.Any("err", fmt.Errorf("plain")))
+	if plain.Value.Kind() != slog.KindAny {
+		t.Errorf("expected plain error to be unchanged, got %v", plain.Value.Kind())
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	err := &foreignError{message: "outer: inner", err: New("inner").With("k1", "v1")}
+
+	buf := &bytes.Buffer{}
+	logger := slog.New(NewSlogHandler(slog.NewJSONHandler(buf, nil)))
+	logger.With("pre", err).Error("failed", "err", err)
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("unmarshal log record: %v", err)
+	}
+
+	for _, key := range []string{"pre", "err"} {
+		e, ok := record[key].(map[string]any)
+		if !ok {
+			t.Fatalf("expected %s group, got %v", key, record[key])
+		}
+
+		if e["message"] != "outer: inner" {
+			t.Errorf("expected message 'outer: inner', got %v", e["message"])
+		}
+	}
+}
+
+type foreignError struct {
+	message string
+	err     error
+}
+
+func (e *foreignError) Error() string {
+	return e.message
+}
+
+func (e *foreignError) Unwrap() error {
+	return e.err
+}
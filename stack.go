@@ -0,0 +1,114 @@
+package errors
+
+import "strconv"
+
+// Frame is a frame of the stack trace of an error
+type Frame struct {
+	File         string `json:"file"`
+	Function     string `json:"function"`
+	Line         int    `json:"line"`
+	Package      string `json:"package,omitempty"`
+	Receiver     string `json:"receiver,omitempty"`
+	FullFunction string `json:"full_function,omitempty"`
+}
+
+// StackOf returns the stack trace of the first error created by this package in the chain of err
+//
+// The chain is walked depth-first through the errors wrapped by other packages,
+// including every branch of joined errors. It returns nil if no error in the
+// chain carries a stack trace.
+func StackOf(err error) []Frame {
+	for err != nil {
+		switch e := err.(type) {
+		case *errorStack:
+			if e != nil {
+				return exportFrames(e.stack.frames())
+			}
+		case *joinError:
+			if e != nil {
+				return exportFrames(e.stack.frames())
+			}
+		}
+
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, err := range u.Unwrap() {
+				if frames := StackOf(err); frames != nil {
+					return frames
+				}
+			}
+			return nil
+		case unwrap:
+			err = u.Unwrap()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// NewWithStack returns an error with the message and the given stack trace instead of
+// capturing the current one, e.g. to rebuild an error received from another process.
+//
+// The fields attached by With are attributed to the first frame of the stack.
+func NewWithStack(text string, stack []Frame) Error {
+	return &errorStack{
+		message: text,
+		cause:   errorString{message: text},
+		stack:   newResolvedStack(importFrames(stack)),
+	}
+}
+
+/*
+	########  ########  #### ##     ##    ###    ######## ########
+	##     ## ##     ##  ##  ##     ##   ## ##      ##    ##
+	##     ## ##     ##  ##  ##     ##  ##   ##     ##    ##
+	########  ########   ##  ##     ## ##     ##    ##    ######
+	##        ##   ##    ##   ##   ##  #########    ##    ##
+	##        ##    ##   ##    ## ##   ##     ##    ##    ##
+	##        ##     ## ####    ###    ##     ##    ##    ########
+*/
+
+// exportFrames converts the resolved frames into Frames
+func exportFrames(frames []frame) []Frame {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	result := make([]Frame, 0, len(frames))
+	for _, f := range frames {
+		line, _ := strconv.Atoi(f.Line)
+		result = append(result, Frame{
+			File:         f.File,
+			Function:     f.Function,
+			Line:         line,
+			Package:      f.Package,
+			Receiver:     f.Receiver,
+			FullFunction: f.FullFunction,
+		})
+	}
+
+	return result
+}
+
+// importFrames converts the Frames into resolved frames
+func importFrames(frames []Frame) []frame {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	result := make([]frame, 0, len(frames))
+	for _, f := range frames {
+		result = append(result, frame{
+			File:         f.File,
+			Function:     f.Function,
+			Line:         strconv.Itoa(f.Line),
+			Package:      f.Package,
+			Receiver:     f.Receiver,
+			FullFunction: f.FullFunction,
+		})
+	}
+
+	return result
+}
@@ -3,17 +3,23 @@ package errors
 import (
 	"fmt"
 	"slices"
+	"time"
 )
 
 // Template is a template for creating errors. It contains args that can be used to create an error.
 type Template struct {
-	attr []attr
+	code       Code
+	attr       []attr
+	class      Class
+	retryAfter time.Duration
+	stack      *StackConfig
+	redaction  *Redaction
 }
 
 // NewTemplate creates a new Template.
 func NewTemplate(args ...any) Template {
 	return Template{
-		attr: makeArgs("", args...),
+		attr: makeArgs(frame{}, args...),
 	}
 }
 
@@ -22,11 +28,10 @@ func NewTemplate(args ...any) Template {
 func (t Template) With(args ...any) Template {
 	attrs := make([]attr, 0, len(t.attr)+len(args)/2)
 	attrs = append(attrs, t.attr...)
-	attrs = append(attrs, makeArgs("", args...)...)
+	attrs = append(attrs, makeArgs(frame{}, args...)...)
 
-	return Template{
-		attr: attrs,
-	}
+	t.attr = attrs
+	return t
 }
 
 // WithMap creates a new Template by appending additional attributes to the existing ones.
@@ -42,9 +47,53 @@ func (t Template) WithMap(m map[string]any) Template {
 		})
 	}
 
-	return Template{
-		attr: attrs,
-	}
+	t.attr = attrs
+	return t
+}
+
+// WithCode creates a new Template which attaches the given code to the errors it creates.
+// It returns a new Template instance without modifying the original one.
+func (t Template) WithCode(code Code) Template {
+	t.code = code
+	t.attr = slices.Clone(t.attr)
+	return t
+}
+
+// WithClass creates a new Template which classifies the errors it creates with the given Class,
+// added to its current Class.
+// It returns a new Template instance without modifying the original one.
+func (t Template) WithClass(class Class) Template {
+	t.class |= class
+	t.attr = slices.Clone(t.attr)
+	return t
+}
+
+// WithRetryAfter creates a new Template which marks the errors it creates as Retryable
+// after the given duration.
+// It returns a new Template instance without modifying the original one.
+func (t Template) WithRetryAfter(d time.Duration) Template {
+	t.class |= Retryable
+	t.retryAfter = d
+	t.attr = slices.Clone(t.attr)
+	return t
+}
+
+// WithStackConfig creates a new Template which captures the stacks of the errors it creates
+// with the given StackConfig instead of the package-level one.
+// It returns a new Template instance without modifying the original one.
+func (t Template) WithStackConfig(cfg StackConfig) Template {
+	t.stack = cfg.clone()
+	t.attr = slices.Clone(t.attr)
+	return t
+}
+
+// WithRedaction creates a new Template which masks the fields of the errors it creates
+// matching the given Redaction, in addition to the package-level one.
+// It returns a new Template instance without modifying the original one.
+func (t Template) WithRedaction(r Redaction) Template {
+	t.redaction = r.clone()
+	t.attr = slices.Clone(t.attr)
+	return t
 }
 
 // New creates a new Error with the given text message and the template's attributes.
@@ -80,11 +129,12 @@ func (t Template) Errorf(format string, args ...any) Error {
 }
 
 // Attrs returns a copy of the template's attributes with the Function field
-// set to the provided lastCaller frame's Function value.
+// set to the provided lastCaller frame's function name.
 func (t Template) Attrs(lastCaller frame) []attr {
 	attrs := slices.Clone(t.attr)
 	for i := range attrs {
 		attrs[i].Function = lastCaller.Function
+		attrs[i].fullFunction = lastCaller.FullFunction
 	}
 
 	return attrs
@@ -92,7 +142,15 @@ func (t Template) Attrs(lastCaller frame) []attr {
 
 // Clone creates a new Template with the same attributes.
 func (t Template) Clone() Template {
-	return Template{
-		attr: slices.Clone(t.attr),
+	t.attr = slices.Clone(t.attr)
+	return t
+}
+
+// stackConfig returns the StackConfig used to capture the stacks of the errors created by the template
+func (t Template) stackConfig() *StackConfig {
+	if t.stack != nil {
+		return t.stack
 	}
+
+	return loadStackConfig()
 }
@@ -60,6 +60,20 @@ func TestTemplateWrap(t *testing.T) {
 	expected := `
 [error] hello, err: root
 [cause] root
+[chain]
+    [0] hello, err: root
+        [TestTemplateWrap] /Users/Shared/Project/personal/go/errors/template_test.go:55
+        [k1] v1
+        [k2] 2
+        [k3] v3
+        [k4] 4
+        [k5] v5
+        [k6] 6
+    [1] root
+        [causeError] /Users/Shared/Project/personal/go/errors/errors_test.go:70
+        [r1] rv1
+        [r2] 22
+    [2] root
 [field]
     [causeError] 
         [r1] rv1
@@ -100,6 +114,20 @@ func TestTemplateWrapf(t *testing.T) {
 	expected := `
 [error] hello world, err: root
 [cause] root
+[chain]
+    [0] hello world, err: root
+        [TestTemplateWrapf] /Users/Shared/Project/personal/go/errors/template_test.go:109
+        [k1] v1
+        [k2] 2
+        [k3] v3
+        [k4] 4
+        [k5] v5
+        [k6] 6
+    [1] root
+        [causeError] /Users/Shared/Project/personal/go/errors/errors_test.go:70
+        [r1] rv1
+        [r2] 22
+    [2] root
 [field]
     [causeError] 
         [r1] rv1
@@ -113,7 +141,7 @@ func TestTemplateWrapf(t *testing.T) {
         [k6] 6
 [stack]
     [causeError] /Users/Shared/Project/personal/go/errors/errors_test.go:70
-    [TestTemplateWrapf] /Users/Shared/Project/personal/go/errors/template_test.go:95
+    [TestTemplateWrapf] /Users/Shared/Project/personal/go/errors/template_test.go:109
 `
 
 	f := FormatColorized(err)
@@ -149,7 +177,7 @@ func TestTemplateErrorf(t *testing.T) {
         [k5] v5
         [k6] 6
 [stack]
-    [TestTemplateErrorf] /Users/Shared/Project/personal/go/errors/template_test.go:135
+    [TestTemplateErrorf] /Users/Shared/Project/personal/go/errors/template_test.go:163
 `
 
 	f := FormatColorized(err)
@@ -176,6 +204,20 @@ func TestTemplateErrorWrap(t *testing.T) {
 	expected := `
 [error] hello world, err: root
 [cause] root
+[chain]
+    [0] hello world, err: root
+        [TestTemplateErrorWrap] /Users/Shared/Project/personal/go/errors/template_test.go:199
+        [k1] v1
+        [k2] 2
+        [k3] v3
+        [k4] 4
+        [k5] v5
+        [k6] 6
+    [1] root
+        [causeError] /Users/Shared/Project/personal/go/errors/errors_test.go:70
+        [r1] rv1
+        [r2] 22
+    [2] root
 [field]
     [causeError] 
         [r1] rv1
@@ -189,7 +231,7 @@ func TestTemplateErrorWrap(t *testing.T) {
         [k6] 6
 [stack]
     [causeError] /Users/Shared/Project/personal/go/errors/errors_test.go:70
-    [TestTemplateErrorWrap] /Users/Shared/Project/personal/go/errors/template_test.go:171
+    [TestTemplateErrorWrap] /Users/Shared/Project/personal/go/errors/template_test.go:199
 `
 
 	f := FormatColorized(err)
@@ -0,0 +1,150 @@
+package errors
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/yanun0323/errors/internal/colorize"
+)
+
+// Theme is the colors of the colorized format
+//
+// Every color is an ANSI escape sequence, e.g. "\x1b[31m", an empty color
+// writes the text without escape sequences.
+type Theme struct {
+	// Error colors the [error] label
+	Error string
+
+	// Code colors the [code] labels
+	Code string
+
+	// Cause colors the [cause] label
+	Cause string
+
+	// Section colors the [chain], [field], [stack] and [join] labels
+	Section string
+
+	// Index colors the indexes of the chain layers and the joined errors
+	Index string
+
+	// Function colors the function names of the fields and the frames
+	Function string
+
+	// Key colors the keys of the fields and the paths of the collected errors
+	Key string
+
+	// Value colors the values of the fields
+	Value string
+
+	// File colors the file:line of the frames
+	File string
+}
+
+var (
+	_theme       atomic.Pointer[Theme]
+	_stderrTheme = sync.OnceValue(func() Theme { return DetectTheme(os.Stderr) })
+)
+
+// DarkTheme returns the Theme for terminals with a dark background
+func DarkTheme() Theme {
+	return Theme{
+		Error:    colorize.BrightRed,
+		Code:     colorize.BrightGreen,
+		Cause:    colorize.BrightYellow,
+		Section:  colorize.BrightCyan,
+		Index:    colorize.BrightYellow,
+		Function: colorize.BrightBlue,
+		Key:      colorize.BrightMagenta,
+		Value:    colorize.White,
+		File:     colorize.BrightBlack,
+	}
+}
+
+// LightTheme returns the Theme for terminals with a light background
+func LightTheme() Theme {
+	return Theme{
+		Error:    colorize.Red,
+		Code:     colorize.Green,
+		Cause:    colorize.Magenta,
+		Section:  colorize.Blue,
+		Index:    colorize.Magenta,
+		Function: colorize.Blue,
+		Key:      colorize.Magenta,
+		Value:    colorize.Black,
+		File:     colorize.BrightBlack,
+	}
+}
+
+// NoColorTheme returns the Theme without colors
+func NoColorTheme() Theme {
+	return Theme{}
+}
+
+// SetTheme sets the Theme of the colorized format, replacing the detected one
+//
+// It is safe to call concurrently with formatting errors.
+func SetTheme(t Theme) {
+	_theme.Store(&t)
+}
+
+// CurrentTheme returns the Theme set by SetTheme, or the Theme detected for os.Stderr
+func CurrentTheme() Theme {
+	return *themeFor(nil)
+}
+
+// DetectTheme returns the Theme for the writer
+//
+// It returns NoColorTheme if the NO_COLOR environment variable is set, or if TERM is
+// "dumb", DarkTheme if the FORCE_COLOR environment variable is set to anything but
+// "0" or "false", and otherwise DarkTheme if w is a terminal and NoColorTheme if not.
+func DetectTheme(w io.Writer) Theme {
+	if os.Getenv("NO_COLOR") != "" {
+		return NoColorTheme()
+	}
+
+	switch force, ok := os.LookupEnv("FORCE_COLOR"); {
+	case ok && force != "0" && force != "false":
+		return DarkTheme()
+	case ok:
+		return NoColorTheme()
+	}
+
+	if os.Getenv("TERM") == "dumb" || !isTerminal(w) {
+		return NoColorTheme()
+	}
+
+	return DarkTheme()
+}
+
+// themeFor returns the Theme set by SetTheme, or the Theme detected for w,
+// the writers which are not files use the Theme detected for os.Stderr
+func themeFor(w io.Writer) *Theme {
+	if t := _theme.Load(); t != nil {
+		return t
+	}
+
+	if f, ok := w.(*os.File); ok && f != os.Stderr {
+		t := DetectTheme(f)
+		return &t
+	}
+
+	t := _stderrTheme()
+	return &t
+}
+
+// isTerminal reports whether the writer is a character device, like a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok || f == nil {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/yanun0323/errors/internal/colorize"
+)
+
+func setTheme(t *testing.T, theme Theme) {
+	t.Helper()
+
+	prev := _theme.Load()
+	SetTheme(theme)
+	t.Cleanup(func() { _theme.Store(prev) })
+}
+
+func TestTheme(t *testing.T) {
+	err := Join(Wrap(New("not found").With("id", 1), "handler"), New("second"))
+
+	setTheme(t, LightTheme())
+	if f := FormatColorized(err); !strings.Contains(f, colorize.Red+"[error] ") || !strings.Contains(f, colorize.Black+"1\n") {
+		t.Errorf("Expected colors of the light theme, got %q", f)
+	}
+
+	if CurrentTheme() != LightTheme() {
+		t.Errorf("Expected current light theme, got %+v", CurrentTheme())
+	}
+
+	setTheme(t, DarkTheme())
+	if f := fmt.Sprintf("%+v", err); !strings.Contains(f, colorize.BrightRed+"[error] ") || strings.Contains(f, colorize.Black) {
+		t.Errorf("Expected colors of the dark theme, got %q", f)
+	}
+
+	setTheme(t, NoColorTheme())
+	f := FormatColorized(err)
+	if strings.Contains(f, "\x1b[") {
+		t.Errorf("Expected no escape sequence, got %q", f)
+	}
+
+	setTheme(t, DarkTheme())
+	if f != colorize.ResetString(FormatColorized(err)) {
+		t.Errorf("Expected the same layout without colors, got\n%s", f)
+	}
+}
+
+func TestDetectTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+	os.Unsetenv("FORCE_COLOR")
+
+	file, err := os.CreateTemp(t.TempDir(), "theme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if DetectTheme(&bytes.Buffer{}) != NoColorTheme() || DetectTheme(file) != NoColorTheme() {
+		t.Error("Expected no color of the writers which are not terminals")
+	}
+
+	t.Setenv("FORCE_COLOR", "1")
+	if DetectTheme(&bytes.Buffer{}) != DarkTheme() {
+		t.Error("Expected colors forced by FORCE_COLOR")
+	}
+
+	t.Setenv("FORCE_COLOR", "0")
+	if DetectTheme(&bytes.Buffer{}) != NoColorTheme() {
+		t.Error("Expected no color of FORCE_COLOR=0")
+	}
+
+	t.Setenv("FORCE_COLOR", "1")
+	t.Setenv("NO_COLOR", "1")
+	if DetectTheme(&bytes.Buffer{}) != NoColorTheme() {
+		t.Error("Expected NO_COLOR to win over FORCE_COLOR")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	if err := WriteColorized(file, New("failed")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(file.Name())
+	if !bytes.Contains(data, []byte(colorize.BrightRed)) {
+		t.Errorf("Expected the theme detected for the file, got %q", data)
+	}
+}
@@ -0,0 +1,175 @@
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+// GroupMode decides which errors are returned by WaitGroup.Wait
+type GroupMode int
+
+const (
+	// CollectAll returns the errors of every failed goroutine, in the order of the Go calls
+	CollectAll GroupMode = iota
+
+	// FirstError returns the first error returned by the goroutines
+	FirstError
+)
+
+// WaitGroup runs goroutines and aggregates their errors, like golang.org/x/sync/errgroup
+//
+// The zero value is ready to use, it has no limit and no context, and collects
+// the errors of every goroutine. The panics of the goroutines are recovered as
+// errors, see Recover.
+//
+// It is named WaitGroup instead of Group, since Group creates the grouped fields of With.
+//
+//	g, ctx := errors.WaitGroupWithContext(ctx)
+//	g.SetLimit(4)
+//	for _, id := range ids {
+//		g.Go(func() error { return fetch(ctx, id) })
+//	}
+//	err := g.Wait() // every failed fetch, with its stack and fields
+type WaitGroup struct {
+	wg     sync.WaitGroup
+	sem    chan struct{}
+	mode   GroupMode
+	cancel context.CancelCauseFunc
+
+	mu    sync.Mutex
+	errs  []error
+	first error
+}
+
+// WaitGroupWithContext returns a new WaitGroup and a context derived from ctx
+//
+// The context is canceled with the first error returned by a goroutine, or when
+// Wait returns, whichever occurs first.
+func WaitGroupWithContext(ctx context.Context) (*WaitGroup, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &WaitGroup{cancel: cancel}, ctx
+}
+
+// SetLimit limits the number of active goroutines to n, a negative n means no limit
+//
+// It must not be called while any goroutine is active.
+func (g *WaitGroup) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+
+	g.sem = make(chan struct{}, n)
+}
+
+// SetMode sets which errors are returned by Wait, CollectAll is used by default
+//
+// It must not be called while any goroutine is active.
+func (g *WaitGroup) SetMode(mode GroupMode) {
+	g.mode = mode
+}
+
+// Go calls fn in a new goroutine, it blocks until the new goroutine can be added
+// without exceeding the limit
+func (g *WaitGroup) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.start(fn)
+}
+
+// TryGo calls fn in a new goroutine only if it does not exceed the limit,
+// it reports whether the goroutine was started
+func (g *WaitGroup) TryGo(fn func() error) bool {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+
+	g.start(fn)
+	return true
+}
+
+// Wait blocks until every goroutine has returned, and returns their errors
+//
+// The returned Error is created like Join, every error keeps its own stack and
+// fields and is rendered as an indexed branch. It returns nil if no goroutine failed.
+//
+// The errors are reset, so the WaitGroup can be reused after Wait returns,
+// but the context of WaitGroupWithContext is done by then.
+func (g *WaitGroup) Wait() Error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.first)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	errs, first := g.errs, g.first
+	g.errs, g.first = nil, nil
+
+	if first == nil {
+		return nil
+	}
+
+	e := &joinError{
+		stack: captureStack(loadStackConfig(), 0),
+	}
+
+	if g.mode == FirstError {
+		e.errs = []error{first}
+		return e
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			e.errs = append(e.errs, err)
+		}
+	}
+
+	return e
+}
+
+func (g *WaitGroup) start(fn func() error) {
+	g.mu.Lock()
+	i := len(g.errs)
+	g.errs = append(g.errs, nil)
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+
+		if err := call(fn); err != nil {
+			g.fail(i, err)
+		}
+	}()
+}
+
+func (g *WaitGroup) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+
+	g.wg.Done()
+}
+
+func (g *WaitGroup) fail(i int, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.errs[i] = err
+	if g.first != nil {
+		return
+	}
+
+	g.first = err
+	if g.cancel != nil {
+		g.cancel(err)
+	}
+}
@@ -0,0 +1,143 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitGroup(t *testing.T) {
+	var g WaitGroup
+	if err := g.Wait(); err != nil {
+		t.Errorf("Expected nil error of an empty group, got %v", err)
+	}
+
+	g.Go(func() error {
+		time.Sleep(10 * time.Millisecond)
+		return New("first").With("task", 1)
+	})
+	g.Go(func() error { return nil })
+	g.Go(func() error { return WithCode(New("third"), "group.third") })
+	g.Go(func() error { panicSite("boom"); return nil })
+
+	err := g.Wait()
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 errors, got %d", len(errs))
+	}
+
+	for i, expected := range []string{"first", "third", "panic: boom"} {
+		if errs[i].Error() != expected {
+			t.Errorf("Expected error %d '%s', got '%s'", i, expected, errs[i].Error())
+		}
+	}
+
+	if v, ok := Lookup(err, "task"); !ok || v != 1 {
+		t.Errorf("Expected field of the goroutine error, got %v", v)
+	}
+
+	if CodeOf(err) != "group.third" {
+		t.Error("Expected code of the goroutine error")
+	}
+
+	var pe *PanicError
+	if !As(err, &pe) {
+		t.Error("Expected the recovered panic")
+	}
+
+	text := Format(err)
+	for _, s := range []string{"[0]:", "[2]:", "panic: boom", "in TestWaitGroup"} {
+		if !strings.Contains(text, s) {
+			t.Errorf("Expected text format to contain '%s', got '%s'", s, text)
+		}
+	}
+
+	var v struct {
+		Join []json.RawMessage `json:"join"`
+	}
+	if e := json.Unmarshal([]byte(FormatJson(err)), &v); e != nil || len(v.Join) != 3 {
+		t.Errorf("Expected 3 errors in JSON, got %v, %s", e, FormatJson(err))
+	}
+
+	g.Go(func() error { return nil })
+	if err := g.Wait(); err != nil {
+		t.Errorf("Expected nil error of a reused group, got %v", err)
+	}
+}
+
+func TestWaitGroupFirstError(t *testing.T) {
+	g, ctx := WaitGroupWithContext(context.Background())
+	g.SetMode(FirstError)
+
+	g.Go(func() error {
+		<-ctx.Done()
+		return Wrap(ctx.Err(), "canceled")
+	})
+	g.Go(func() error { return errPanicSentinel })
+
+	err := g.Wait()
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(errs) != 1 || errs[0] != errPanicSentinel {
+		t.Errorf("Expected only the first error, got %v", errs)
+	}
+
+	if !Is(context.Cause(ctx), errPanicSentinel) {
+		t.Errorf("Expected context canceled by the first error, got %v", context.Cause(ctx))
+	}
+
+	g, ctx = WaitGroupWithContext(context.Background())
+	if err := g.Wait(); err != nil || ctx.Err() == nil {
+		t.Errorf("Expected context canceled by Wait, got %v, %v", err, ctx.Err())
+	}
+}
+
+func TestWaitGroupLimit(t *testing.T) {
+	var g WaitGroup
+	g.SetLimit(2)
+
+	var active, peak atomic.Int32
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			n := active.Add(1)
+			defer active.Add(-1)
+
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+
+	if peak.Load() > 2 {
+		t.Errorf("Expected at most 2 active goroutines, got %d", peak.Load())
+	}
+
+	g.SetLimit(1)
+	release := make(chan struct{})
+	if !g.TryGo(func() error { <-release; return nil }) {
+		t.Error("Expected goroutine started under the limit")
+	}
+
+	if g.TryGo(func() error { return nil }) {
+		t.Error("Expected goroutine rejected over the limit")
+	}
+
+	close(release)
+	_ = g.Wait()
+}
@@ -0,0 +1,220 @@
+package errors
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yanun0323/errors/internal/colorize"
+)
+
+const _errorPadding = "        " // len("[error] ")
+
+// textWriter is the writer the text and colorized formats are written to
+type textWriter = colorize.Writer
+
+// WriteText writes the text format of the error to w, it writes the same output as Format
+// without building an intermediate string
+func WriteText(w io.Writer, err error) error {
+	return writeFormatted(w, err, nil, false)
+}
+
+// WriteJSON writes the JSON format of the error to w, it writes the same output as FormatJson
+func WriteJSON(w io.Writer, err error) error {
+	if err == nil {
+		_, e := io.WriteString(w, _emptyJSONString)
+		return e
+	}
+
+	if f, ok := err.(formattable); ok {
+		return writeJSON(w, f.toJSON())
+	}
+
+	_, e := io.WriteString(w, err.Error())
+	return e
+}
+
+// WriteColorized writes the colorized format of the error to w without building an intermediate string
+//
+// The colors are the Theme set by SetTheme, or the Theme detected for w by DetectTheme
+// if no Theme is set. The writers which are not files use the Theme detected for os.Stderr.
+func WriteColorized(w io.Writer, err error) error {
+	return writeFormatted(w, err, themeFor(w), false)
+}
+
+/*
+	########  ########  #### ##     ##    ###    ######## ########
+	##     ## ##     ##  ##  ##     ##   ## ##      ##    ##
+	##     ## ##     ##  ##  ##     ##  ##   ##     ##    ##
+	########  ########   ##  ##     ## ##     ##    ##    ######
+	##        ##   ##    ##   ##   ##  #########    ##    ##
+	##        ##    ##   ##    ## ##   ##     ##    ##    ##
+	##        ##     ## ####    ###    ##     ##    ##    ########
+*/
+
+// writeFormatted writes the text format of the error to w,
+// or the colorized format if the theme is not nil
+func writeFormatted(w io.Writer, err error, t *Theme, full bool) error {
+	if err == nil {
+		return nil
+	}
+
+	f, ok := err.(formattable)
+	if !ok {
+		_, e := io.WriteString(w, err.Error())
+		return e
+	}
+
+	return writeBuffered(w, func(buf textWriter) {
+		buf.WriteByte('\n')
+		if t != nil {
+			f.writeColorized(buf, t, full)
+			return
+		}
+
+		f.writeText(buf, full)
+	})
+}
+
+// writeBuffered calls write with w, w is buffered if it is not an in-memory buffer
+// or the fmt.State of a fmt verb
+func writeBuffered(w io.Writer, write func(buf textWriter)) error {
+	switch buf := w.(type) {
+	case *strings.Builder:
+		write(buf)
+		return nil
+	case *bytes.Buffer:
+		write(buf)
+		return nil
+	case *stateWriter:
+		write(buf)
+		return nil
+	}
+
+	buf := bufioWriterPool.Get().(*bufio.Writer)
+	defer bufioWriterPool.Put(buf)
+	buf.Reset(w)
+
+	write(buf)
+	err := buf.Flush()
+	buf.Reset(nil)
+
+	return err
+}
+
+// stateWriter writes to the fmt.State of a fmt verb, which buffers the output itself
+type stateWriter struct {
+	fmt.State
+	s io.StringWriter
+	b [1]byte
+}
+
+func newStateWriter(f fmt.State) *stateWriter {
+	w := &stateWriter{State: f}
+	if s, ok := f.(io.StringWriter); ok {
+		w.s = s
+	}
+
+	return w
+}
+
+func (w *stateWriter) WriteString(s string) (int, error) {
+	if w.s != nil {
+		return w.s.WriteString(s)
+	}
+
+	return w.State.Write([]byte(s))
+}
+
+func (w *stateWriter) WriteByte(c byte) error {
+	w.b[0] = c
+	_, err := w.State.Write(w.b[:])
+	return err
+}
+
+// indentWriter writes every line with the indent
+type indentWriter struct {
+	w      textWriter
+	indent string
+	bol    bool
+}
+
+func newIndentWriter(w textWriter, indent string) *indentWriter {
+	return &indentWriter{w: w, indent: indent, bol: true}
+}
+
+func (w *indentWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) != 0 {
+		if w.bol {
+			w.w.WriteString(w.indent)
+			w.bol = false
+		}
+
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.w.Write(p)
+			break
+		}
+
+		w.w.Write(p[:i+1])
+		p = p[i+1:]
+		w.bol = true
+	}
+
+	return n, nil
+}
+
+func (w *indentWriter) WriteString(s string) (int, error) {
+	n := len(s)
+	for len(s) != 0 {
+		if w.bol {
+			w.w.WriteString(w.indent)
+			w.bol = false
+		}
+
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			w.w.WriteString(s)
+			break
+		}
+
+		w.w.WriteString(s[:i+1])
+		s = s[i+1:]
+		w.bol = true
+	}
+
+	return n, nil
+}
+
+func (w *indentWriter) WriteByte(c byte) error {
+	if w.bol {
+		w.w.WriteString(w.indent)
+	}
+
+	w.bol = c == '\n'
+	return w.w.WriteByte(c)
+}
+
+// writeIndented writes every line of s with the given indent
+func writeIndented(buf textWriter, indent string, s string) {
+	for _, line := range strings.SplitAfter(strings.TrimSuffix(s, "\n"), "\n") {
+		buf.WriteString(indent)
+		buf.WriteString(line)
+	}
+	buf.WriteByte('\n')
+}
+
+// writeValueColorized writes the value of a field with the color, followed by a newline
+func writeValueColorized(buf textWriter, color string, value any) {
+	if color == "" {
+		fmt.Fprintf(buf, "%+v\n", value)
+		return
+	}
+
+	buf.WriteString(color)
+	fmt.Fprintf(buf, "%+v\n", value)
+	buf.WriteString(colorize.Reset)
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestWrite(t *testing.T) {
+	errs := []error{
+		nil,
+		context.Canceled,
+		Wrap(New("not found").With("id", 1), "handler"),
+		Combine(New("first"), Join(New("second"), context.Canceled)).With("batch", 2),
+	}
+
+	for _, err := range errs {
+		for _, tc := range []struct {
+			name   string
+			write  func(io.Writer, error) error
+			format func(error) string
+		}{
+			{"text", WriteText, Format},
+			{"json", WriteJSON, FormatJson},
+			{"colorized", WriteColorized, FormatColorized},
+		} {
+			// bytes.Buffer is written directly, os.File through a buffer
+			var buf bytes.Buffer
+			if e := tc.write(&buf, err); e != nil || buf.String() != tc.format(err) {
+				t.Errorf("Expected %s output equal to the format of %v, got %v\n%s", tc.name, err, e, buf.String())
+			}
+
+			f, e := os.CreateTemp(t.TempDir(), "write")
+			if e != nil {
+				t.Fatal(e)
+			}
+
+			if e := tc.write(f, err); e != nil {
+				t.Errorf("Expected %s written to file, got %v", tc.name, e)
+			}
+
+			data, _ := os.ReadFile(f.Name())
+			_ = f.Close()
+
+			if string(data) != tc.format(err) {
+				t.Errorf("Expected %s file output equal to the format of %v, got\n%s", tc.name, err, data)
+			}
+		}
+	}
+
+	err := Wrap(New("not found"), "handler")
+	if e := WriteText(failingWriter{}, err); e != io.ErrClosedPipe {
+		t.Errorf("Expected error of the writer, got %v", e)
+	}
+
+	if e := WriteJSON(failingWriter{}, err); e != io.ErrClosedPipe {
+		t.Errorf("Expected error of the writer, got %v", e)
+	}
+}
+
+func TestWriteJSONAfterFailingWriter(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		if e := WriteJSON(failingWriter{}, New("a")); e != io.ErrClosedPipe {
+			t.Errorf("Expected error of the writer, got %v", e)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if f := FormatJson(New("b")); !containsString(f, `"error": "b"`) {
+			t.Fatalf("Expected JSON of the error after a failing writer, got '%s'", f)
+		}
+	}
+
+	var buf bytes.Buffer
+	if e := WriteJSON(&buf, New("c")); e != nil || !containsString(buf.String(), `"error": "c"`) {
+		t.Errorf("Expected JSON of the error after a failing writer, got '%s' %v", buf.String(), e)
+	}
+}